@@ -43,4 +43,72 @@ resource "aws_instance" "node" {
         device_name = "/dev/xvdc"
         delete_on_termination = true
     }
-}
\ No newline at end of file
+}
+#
+# Auto scaling group for autoscale tests, empty until scaled via API
+#
+
+resource "aws_launch_configuration" "scaled" {
+    name_prefix          = "${var.cluster_name}-"
+    image_id             = "${lookup(var.ami, var.os)}"
+    instance_type        = "${var.instance_type}"
+    ebs_optimized        = true
+    security_groups      = ["${aws_security_group.cluster.id}"]
+    key_name             = "${var.key_pair}"
+    iam_instance_profile = "robotest-node"
+    associate_public_ip_address = true
+
+    user_data = "${file("./bootstrap/${var.os}.sh")}"
+
+    root_block_device {
+        volume_type = "gp2"
+        volume_size = "60"
+        delete_on_termination = true
+    }
+
+    ebs_block_device = {
+        volume_type = "gp2"
+        volume_size = "80"
+        device_name = "${var.docker_device}"
+        delete_on_termination = true
+    }
+
+    ebs_block_device = {
+        volume_type = "io1"
+        iops = 1500
+        volume_size = "30"
+        device_name = "/dev/xvdc"
+        delete_on_termination = true
+    }
+
+    lifecycle {
+        create_before_destroy = true
+    }
+}
+
+resource "aws_autoscaling_group" "scaled" {
+    name                 = "${var.cluster_name}"
+    launch_configuration = "${aws_launch_configuration.scaled.name}"
+    availability_zones   = ["${aws_instance.node.0.availability_zone}"]
+    placement_group      = "${aws_placement_group.cluster.id}"
+    min_size             = 0
+    max_size             = 10
+    desired_capacity     = 0
+
+    tag {
+        key                 = "Name"
+        value               = "${var.cluster_name}"
+        propagate_at_launch = true
+    }
+
+    tag {
+        key                 = "Origin"
+        value               = "robotest"
+        propagate_at_launch = true
+    }
+
+    # capacity is managed by tests
+    lifecycle {
+        ignore_changes = ["desired_capacity"]
+    }
+}
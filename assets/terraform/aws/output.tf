@@ -5,3 +5,7 @@ output "private_ips" {
 output "public_ips" {
   value = "${join(" ", aws_instance.node.*.public_ip)}"
 }
+
+output "scaling_group" {
+  value = "${aws_autoscaling_group.scaled.name}"
+}
@@ -93,3 +93,85 @@ data "azurerm_public_ip" "node" {
   resource_group_name = "${azurerm_resource_group.robotest.name}"
   depends_on          = ["azurerm_virtual_machine.node"]
 }
+
+#
+# VM scale set for autoscale tests, empty until scaled via API
+#
+
+resource "azurerm_virtual_machine_scale_set" "scaled" {
+  name                = "scaled"
+  location            = "${var.location}"
+  resource_group_name = "${azurerm_resource_group.robotest.name}"
+  upgrade_policy_mode = "Manual"
+
+  sku {
+    name     = "${var.vm_type}"
+    tier     = "Standard"
+    capacity = 0
+  }
+
+  storage_profile_image_reference {
+    publisher = "${lookup(var.os_publisher, element(split(":",var.os),0))}"
+    offer     = "${lookup(var.os_offer,     element(split(":",var.os),0))}"
+    sku       = "${lookup(var.os_sku,       var.os)}"
+    version   = "${lookup(var.os_version,   var.os)}"
+  }
+
+  storage_profile_os_disk {
+    caching           = "ReadWrite"
+    create_option     = "FromImage"
+    managed_disk_type = "Premium_LRS"
+  }
+
+  storage_profile_data_disk {
+    managed_disk_type = "Premium_LRS"
+    create_option     = "Empty"
+    lun               = 0
+    disk_size_gb      = "64"
+  }
+
+  storage_profile_data_disk {
+    managed_disk_type = "Premium_LRS"
+    create_option     = "Empty"
+    lun               = 1
+    disk_size_gb      = "64"
+  }
+
+  os_profile {
+    custom_data          = "${file("./bootstrap/${element(split(":",var.os),0)}.sh")}"
+    computer_name_prefix = "scaled"
+    admin_username       = "${var.ssh_user}"
+    admin_password       = "${var.random_password}"
+  }
+
+  os_profile_linux_config {
+    disable_password_authentication = true
+    ssh_keys = {
+        path = "/home/${var.ssh_user}/.ssh/authorized_keys"
+        key_data = "${file("${var.ssh_authorized_keys_path}")}"
+    }
+  }
+
+  network_profile {
+    name                      = "scaled"
+    primary                   = true
+    network_security_group_id = "${azurerm_network_security_group.robotest.id}"
+
+    ip_configuration {
+      name      = "ipconfig"
+      primary   = true
+      subnet_id = "${azurerm_subnet.robotest_a.id}"
+
+      public_ip_address_configuration {
+        name              = "scaled"
+        idle_timeout      = 4
+        domain_name_label = "${lower(var.azure_resource_group)}"
+      }
+    }
+  }
+
+  # capacity is managed by tests
+  lifecycle {
+    ignore_changes = ["sku"]
+  }
+}
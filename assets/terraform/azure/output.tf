@@ -10,3 +10,7 @@ output "private_ips" {
 output "public_ips" {
   value = "${join(" ", data.azurerm_public_ip.node.*.ip_address)}"
 }
+
+output "scaling_group" {
+  value = "${azurerm_virtual_machine_scale_set.scaled.name}"
+}
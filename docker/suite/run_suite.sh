@@ -101,6 +101,7 @@ installer_url: ${INSTALLER_FILE:-${INSTALLER_URL}}
 script_path: /robotest/terraform/${DEPLOY_TO}
 state_dir: /robotest/state
 cloud: ${DEPLOY_TO}
+fix_time_sync: ${FIX_TIME_SYNC:-false}
 ${AWS_CONFIG:-}
 ${AZURE_CONFIG:-}
 ${OPS_CONFIG:-}
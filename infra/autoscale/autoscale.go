@@ -0,0 +1,62 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+)
+
+// Instance is a VM instance managed by a scaling group
+type Instance struct {
+	// ID is cloud specific instance ID
+	ID string
+	// PublicAddr is public IP address of the instance
+	PublicAddr string
+	// PrivateAddr is private IP address of the instance
+	PrivateAddr string
+}
+
+// AutoScaler manages a group of identical VMs, i.e. AWS auto scaling group or Azure VM scale set
+type AutoScaler interface {
+	// SetDesired requests scaling group to have target number of instances
+	SetDesired(ctx context.Context, target int) error
+	// ListInstances returns running instances of the scaling group
+	ListInstances(ctx context.Context) ([]Instance, error)
+	// Remove terminates instance with given ID and decrements desired size of the scaling group
+	Remove(ctx context.Context, id string) error
+}
+
+// Scale sets desired size of the scaling group and waits until it has exactly target instances running
+func Scale(ctx context.Context, scaler AutoScaler, target int, retry wait.Retryer) ([]Instance, error) {
+	if target < 0 {
+		return nil, trace.BadParameter("target size should not be negative, got %v", target)
+	}
+
+	err := scaler.SetDesired(ctx, target)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	// instances may take a while to get assigned to the group
+	// so repeat requests until we get the expected number
+	var instances []Instance
+	err = retry.Do(ctx, func() (err error) {
+		instances, err = scaler.ListInstances(ctx)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if len(instances) != target {
+			return wait.Continue(fmt.Sprintf("unexpected count of instances. expected: %v got: %v",
+				target, len(instances)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return instances, nil
+}
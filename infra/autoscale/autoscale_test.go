@@ -0,0 +1,54 @@
+package autoscale
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScale(t *testing.T) {
+	ctx := context.Background()
+	retry := wait.Retryer{Delay: time.Millisecond, Attempts: 10}
+
+	scaler := NewFake(1)
+
+	instances, err := Scale(ctx, scaler, 3, retry)
+	require.NoError(t, err, "scale up")
+	require.Len(t, instances, 3)
+	assert.Equal(t, Instance{ID: "i-1", PublicAddr: "192.168.0.1", PrivateAddr: "10.0.0.1"}, instances[0])
+
+	instances, err = Scale(ctx, scaler, 1, retry)
+	require.NoError(t, err, "scale down")
+	require.Len(t, instances, 1)
+	assert.Equal(t, "i-3", instances[0].ID, "oldest instances are removed first")
+
+	_, err = Scale(ctx, scaler, 5, wait.Retryer{Delay: time.Millisecond, Attempts: 2})
+	assert.Error(t, err, "group should not converge within 2 attempts")
+
+	_, err = Scale(ctx, scaler, -1, retry)
+	assert.Error(t, err, "negative target")
+}
+
+func TestRemove(t *testing.T) {
+	ctx := context.Background()
+	scaler := NewFake(3)
+
+	require.NoError(t, scaler.Remove(ctx, "i-2"))
+	instances, err := scaler.ListInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "i-1", instances[0].ID)
+	assert.Equal(t, "i-3", instances[1].ID)
+
+	instances, err = scaler.ListInstances(ctx)
+	require.NoError(t, err)
+	assert.Len(t, instances, 2, "group should not replace removed instance")
+
+	assert.True(t, trace.IsNotFound(scaler.Remove(ctx, "i-2")), "instance already removed")
+}
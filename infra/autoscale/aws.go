@@ -0,0 +1,116 @@
+package autoscale
+
+import (
+	"context"
+
+	"github.com/gravitational/trace"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AWSConfig defines AWS auto scaling group connection parameters
+type AWSConfig struct {
+	// AccessKey http://docs.aws.amazon.com/general/latest/gr/managing-aws-access-keys.html
+	AccessKey string
+	// SecretKey http://docs.aws.amazon.com/general/latest/gr/managing-aws-access-keys.html
+	SecretKey string
+	// Region is EC2 region of the group
+	Region string
+	// GroupName is auto scaling group name
+	GroupName string
+}
+
+type awsScaler struct {
+	group string
+	asg   *autoscaling.AutoScaling
+	ec2   *ec2.EC2
+}
+
+// NewAWS returns AutoScaler backed by AWS auto scaling group
+func NewAWS(cfg AWSConfig) (AutoScaler, error) {
+	if cfg.GroupName == "" {
+		return nil, trace.BadParameter("missing auto scaling group name")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(cfg.Region),
+		Credentials: credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &awsScaler{
+		group: cfg.GroupName,
+		asg:   autoscaling.New(sess),
+		ec2:   ec2.New(sess),
+	}, nil
+}
+
+// SetDesired sets desired capacity of the group
+func (r *awsScaler) SetDesired(ctx context.Context, target int) error {
+	_, err := r.asg.SetDesiredCapacityWithContext(ctx, &autoscaling.SetDesiredCapacityInput{
+		AutoScalingGroupName: aws.String(r.group),
+		DesiredCapacity:      aws.Int64(int64(target)),
+		HonorCooldown:        aws.Bool(false),
+	})
+	return trace.Wrap(err)
+}
+
+// Remove terminates instance, decrementing desired capacity of the group
+func (r *awsScaler) Remove(ctx context.Context, id string) error {
+	_, err := r.asg.TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(id),
+		ShouldDecrementDesiredCapacity: aws.Bool(true),
+	})
+	return trace.Wrap(err)
+}
+
+// ListInstances returns running instances of the group
+func (r *awsScaler) ListInstances(ctx context.Context) ([]Instance, error) {
+	result, err := r.asg.DescribeAutoScalingGroupsWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(r.group)},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	if len(result.AutoScalingGroups) != 1 {
+		return nil, trace.BadParameter("unexpected number of autoscaling groups found: 1 != %v", len(result.AutoScalingGroups))
+	}
+
+	ids := []*string{}
+	for _, instance := range result.AutoScalingGroups[0].Instances {
+		if aws.StringValue(instance.LifecycleState) != autoscaling.LifecycleStateInService {
+			continue
+		}
+		ids = append(ids, instance.InstanceId)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	resp, err := r.ec2.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: ids})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	instances := []Instance{}
+	for _, reservation := range resp.Reservations {
+		for _, inst := range reservation.Instances {
+			if inst.State == nil || aws.StringValue(inst.State.Name) != ec2.InstanceStateNameRunning {
+				continue
+			}
+			instances = append(instances, Instance{
+				ID:          aws.StringValue(inst.InstanceId),
+				PublicAddr:  aws.StringValue(inst.PublicIpAddress),
+				PrivateAddr: aws.StringValue(inst.PrivateIpAddress),
+			})
+		}
+	}
+	return instances, nil
+}
@@ -0,0 +1,149 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gravitational/robotest/infra/azure"
+	"github.com/gravitational/robotest/infra/terraform"
+
+	"github.com/gravitational/trace"
+)
+
+// AzureConfig defines Azure VM scale set connection parameters
+type AzureConfig struct {
+	terraform.AzureAuthParam
+	// SubscriptionId is Azure subscription
+	SubscriptionId string
+	// ResourceGroup is resource group of the scale set
+	ResourceGroup string
+	// ScaleSet is VM scale set name
+	ScaleSet string
+}
+
+const (
+	azureScaleSetUrl   = azure.ManagementURL + "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets/%s"
+	azureStateSucceded = "Succeeded"
+)
+
+type azureScaler struct {
+	AzureConfig
+	client *azure.Client
+}
+
+// NewAzure returns AutoScaler backed by Azure VM scale set
+func NewAzure(cfg AzureConfig) (AutoScaler, error) {
+	if cfg.SubscriptionId == "" || cfg.ResourceGroup == "" || cfg.ScaleSet == "" {
+		return nil, trace.BadParameter("subscription=%q, group=%q, scale set=%q",
+			cfg.SubscriptionId, cfg.ResourceGroup, cfg.ScaleSet)
+	}
+	return &azureScaler{AzureConfig: cfg, client: azure.NewClient(cfg.AzureAuthParam)}, nil
+}
+
+// SetDesired updates scale set capacity
+func (r *azureScaler) SetDesired(ctx context.Context, target int) error {
+	body := map[string]interface{}{
+		"sku": map[string]interface{}{"capacity": target},
+	}
+	return trace.Wrap(r.client.Do(ctx, "PATCH", fmt.Sprintf("%s?%s", r.scaleSetUrl(), azure.ComputeAPI), body, nil))
+}
+
+// Remove deletes VM instance from the scale set, which also reduces its capacity
+func (r *azureScaler) Remove(ctx context.Context, id string) error {
+	body := map[string]interface{}{
+		"instanceIds": []string{id},
+	}
+	return trace.Wrap(r.client.Do(ctx, "POST", fmt.Sprintf("%s/delete?%s", r.scaleSetUrl(), azure.ComputeAPI), body, nil))
+}
+
+// azureVMs is a subset of scale set VM list response
+type azureVMs struct {
+	Value []struct {
+		ID         string `json:"id"`
+		InstanceID string `json:"instanceId"`
+		Properties struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	} `json:"value"`
+}
+
+// azureNICs is a subset of scale set network interfaces list response
+type azureNICs struct {
+	Value []struct {
+		Properties struct {
+			VirtualMachine struct {
+				ID string `json:"id"`
+			} `json:"virtualMachine"`
+			IPConfigurations []struct {
+				ID         string `json:"id"`
+				Properties struct {
+					PrivateIPAddress string `json:"privateIPAddress"`
+				} `json:"properties"`
+			} `json:"ipConfigurations"`
+		} `json:"properties"`
+	} `json:"value"`
+}
+
+// azurePublicIPs is a subset of scale set public IP addresses list response
+type azurePublicIPs struct {
+	Value []struct {
+		Properties struct {
+			IPAddress       string `json:"ipAddress"`
+			IPConfiguration struct {
+				ID string `json:"id"`
+			} `json:"ipConfiguration"`
+		} `json:"properties"`
+	} `json:"value"`
+}
+
+// ListInstances returns successfully provisioned VMs of the scale set
+func (r *azureScaler) ListInstances(ctx context.Context) ([]Instance, error) {
+	var vms azureVMs
+	err := r.client.Do(ctx, "GET", fmt.Sprintf("%s/virtualMachines?%s", r.scaleSetUrl(), azure.ComputeAPI), nil, &vms)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	var nics azureNICs
+	err = r.client.Do(ctx, "GET", fmt.Sprintf("%s/networkInterfaces?%s", r.scaleSetUrl(), azure.NetworkAPI), nil, &nics)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	var publicIPs azurePublicIPs
+	err = r.client.Do(ctx, "GET", fmt.Sprintf("%s/publicipaddresses?%s", r.scaleSetUrl(), azure.NetworkAPI), nil, &publicIPs)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	// public IPs are linked to IP configurations of network interfaces
+	publicByConfig := map[string]string{}
+	for _, ip := range publicIPs.Value {
+		publicByConfig[strings.ToLower(ip.Properties.IPConfiguration.ID)] = ip.Properties.IPAddress
+	}
+
+	instances := []Instance{}
+	for _, vm := range vms.Value {
+		if vm.Properties.ProvisioningState != azureStateSucceded {
+			continue
+		}
+		instance := Instance{ID: vm.InstanceID}
+		for _, nic := range nics.Value {
+			if !strings.EqualFold(nic.Properties.VirtualMachine.ID, vm.ID) {
+				continue
+			}
+			for _, config := range nic.Properties.IPConfigurations {
+				instance.PrivateAddr = config.Properties.PrivateIPAddress
+				instance.PublicAddr = publicByConfig[strings.ToLower(config.ID)]
+				break
+			}
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func (r *azureScaler) scaleSetUrl() string {
+	return fmt.Sprintf(azureScaleSetUrl, r.SubscriptionId, r.ResourceGroup, r.ScaleSet)
+}
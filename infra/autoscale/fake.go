@@ -0,0 +1,80 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gravitational/trace"
+)
+
+// Fake is an in-memory AutoScaler for unit tests.
+// Every call to ListInstances moves group size one instance closer to desired
+type Fake struct {
+	sync.Mutex
+	desired   int
+	instances []Instance
+	next      int
+}
+
+// NewFake returns fake scaling group with initial number of instances
+func NewFake(initial int) *Fake {
+	f := &Fake{desired: initial}
+	for i := 0; i < initial; i++ {
+		f.add()
+	}
+	return f
+}
+
+// SetDesired records desired group size
+func (f *Fake) SetDesired(ctx context.Context, target int) error {
+	if target < 0 {
+		return trace.BadParameter("negative target %v", target)
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.desired = target
+	return nil
+}
+
+// ListInstances returns current instances, converging by one instance towards desired size
+func (f *Fake) ListInstances(ctx context.Context) ([]Instance, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	out := make([]Instance, len(f.instances))
+	copy(out, f.instances)
+
+	switch {
+	case len(f.instances) < f.desired:
+		f.add()
+	case len(f.instances) > f.desired:
+		f.instances = f.instances[1:]
+	}
+	return out, nil
+}
+
+// Remove removes instance immediately and decrements desired group size
+func (f *Fake) Remove(ctx context.Context, id string) error {
+	f.Lock()
+	defer f.Unlock()
+
+	for i, instance := range f.instances {
+		if instance.ID == id {
+			f.instances = append(f.instances[:i], f.instances[i+1:]...)
+			f.desired--
+			return nil
+		}
+	}
+	return trace.NotFound("instance %v not found", id)
+}
+
+func (f *Fake) add() {
+	f.next++
+	f.instances = append(f.instances, Instance{
+		ID:          fmt.Sprintf("i-%d", f.next),
+		PublicAddr:  fmt.Sprintf("192.168.0.%d", f.next),
+		PrivateAddr: fmt.Sprintf("10.0.0.%d", f.next),
+	})
+}
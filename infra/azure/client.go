@@ -0,0 +1,83 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gravitational/robotest/infra/terraform"
+
+	"github.com/gravitational/trace"
+)
+
+const (
+	// ManagementURL is Azure Resource Manager endpoint, resource IDs are relative to it
+	ManagementURL = "https://management.azure.com"
+	// ComputeAPI is API version of Microsoft.Compute resources
+	ComputeAPI = "api-version=2019-03-01"
+	// NetworkAPI is API version of Microsoft.Network resources
+	NetworkAPI = "api-version=2017-03-30"
+)
+
+// Client is Azure Resource Manager REST API client
+type Client struct {
+	param  terraform.AzureAuthParam
+	client *http.Client
+}
+
+// NewClient returns REST API client authenticating as application
+func NewClient(param terraform.AzureAuthParam) *Client {
+	return &Client{param: param, client: &http.Client{}}
+}
+
+// Do sends request with JSON encoded in, if not nil,
+// and decodes JSON response into out, if not nil
+func (r *Client) Do(ctx context.Context, method, reqUrl string, in, out interface{}) error {
+	token, err := terraform.AzureGetAuthToken(ctx, r.param)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, reqUrl, body)
+	if err != nil {
+		return trace.Wrap(err, `[%s %s]=%v`, method, reqUrl, err)
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Add("Authorization", fmt.Sprintf("%s %s", token.Type, token.Token))
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return trace.Wrap(err, "[read response from %s %s]=%v", method, reqUrl, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return trace.Errorf("%v/%s [%s %s]: %s", resp.StatusCode, resp.Status, method, reqUrl, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return trace.Wrap(err, "%v : data=%q", err, data)
+	}
+	return nil
+}
@@ -27,6 +27,8 @@ type ProvisionerState struct {
 	Allocated []string `json:"allocated_nodes"`
 	// LoadBalancerAddr defines the dns name of the loadbalancer
 	LoadBalancerAddr string `json:"loadbalancer"`
+	// ScalingGroup is the name of auto scaling group / VM scale set, if provisioned
+	ScalingGroup string `json:"scaling_group,omitempty"`
 }
 
 // StateNode describes a single cluster node
@@ -0,0 +1,131 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// airGapChain is iptables chain holding egress rules of air-gapped nodes
+const airGapChain = "ROBOTEST-AIRGAP"
+
+// AirGap blocks new outbound connections from nodes except to each other and allowed nodes, to simulate
+// air-gapped environment, until the end of the test. Replies to inbound connections,
+// i.e. SSH from the test host, as well as DNS and link-local (cloud metadata) traffic are allowed.
+// Installers subsequently transferred to the nodes from remote URLs are downloaded
+// with egress temporarily restored, as they would be delivered out of band
+func (c *TestContext) AirGap(nodes []Gravity, allowed ...Gravity) error {
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+
+	c.Logger().WithField("nodes", nodes).Warn("FAULT: air gap")
+	c.OnTeardown("lift air gap", func(ctx context.Context) error {
+		return trace.Wrap(c.liftAirGap(ctx, nodes))
+	})
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	peers := append(append([]Gravity{}, nodes...), allowed...)
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(n Gravity) {
+			errs <- trace.Wrap(airGapNode(ctx, n, peers), n.String())
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
+
+func (c *TestContext) liftAirGap(ctx context.Context, nodes []Gravity) error {
+	var errors []error
+	for _, node := range nodes {
+		if node.Offline() {
+			continue
+		}
+		if g, ok := node.(*gravity); ok {
+			g.airGapped = false
+		}
+		err := setEgress(ctx, node, true)
+		if err != nil {
+			errors = append(errors, trace.Wrap(err, "lifting air gap on %v", node))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// airGapNode rejects new connections leaving node private interface, including forwarded
+// traffic of containers, unless they are destined to one of the peers
+func airGapNode(ctx context.Context, node Gravity, peers []Gravity) error {
+	cmds := []sshutils.Cmd{
+		{Command: fmt.Sprintf("sudo iptables -N %[1]s || sudo iptables -F %[1]s", airGapChain)},
+		{Command: fmt.Sprintf("sudo iptables -A %s -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN", airGapChain)},
+		{Command: fmt.Sprintf("sudo iptables -A %s -d 169.254.0.0/16 -j RETURN", airGapChain)},
+		{Command: fmt.Sprintf("sudo iptables -A %s -p udp --dport 53 -j RETURN", airGapChain)},
+		{Command: fmt.Sprintf("sudo iptables -A %s -p tcp --dport 53 -j RETURN", airGapChain)},
+	}
+	for _, peer := range peers {
+		cmds = append(cmds, sshutils.Cmd{
+			Command: fmt.Sprintf("sudo iptables -A %s -d %s -j RETURN", airGapChain, peer.Node().PrivateAddr())})
+	}
+	cmds = append(cmds, sshutils.Cmd{Command: fmt.Sprintf("sudo iptables -A %s -j REJECT", airGapChain)})
+
+	err := sshutils.RunCommands(ctx, node.Client(), node.Logger(), cmds)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = setEgress(ctx, node, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if g, ok := node.(*gravity); ok {
+		g.airGapped = true
+	}
+	return nil
+}
+
+// setEgress removes or inserts jumps to air gap chain from OUTPUT and FORWARD chains
+// for traffic leaving node private interface
+func setEgress(ctx context.Context, node Gravity, allow bool) error {
+	cmds := []sshutils.Cmd{}
+	for _, chain := range []string{"OUTPUT", "FORWARD"} {
+		rule := fmt.Sprintf("%s -o $IFACE -j %s", chain, airGapChain)
+		cmd := fmt.Sprintf("%s && (sudo iptables -C %[2]s 2>/dev/null || sudo iptables -I %[2]s)",
+			privateIfaceCmd(node), rule)
+		if allow {
+			cmd = fmt.Sprintf("%s && (sudo iptables -D %s 2>/dev/null || true)", privateIfaceCmd(node), rule)
+		}
+		cmds = append(cmds, sshutils.Cmd{Command: cmd})
+	}
+	return trace.Wrap(sshutils.RunCommands(ctx, node.Client(), node.Logger(), cmds))
+}
+
+// withEgress runs fn with egress restored if node is air-gapped and file has to be downloaded
+func (g *gravity) withEgress(ctx context.Context, fileURL string, fn func() error) error {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return trace.Wrap(err, "parsing %s", fileURL)
+	}
+	if !g.airGapped || u.Scheme == "" {
+		return fn()
+	}
+
+	g.Logger().WithField("url", fileURL).Info("restore egress for download")
+	err = setEgress(ctx, g, true)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	fnErr := fn()
+	g.Logger().Info("block egress after download")
+	err = setEgress(ctx, g, false)
+	return trace.NewAggregate(fnErr, err)
+}
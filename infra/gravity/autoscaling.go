@@ -1,108 +1,113 @@
 package gravity
 
 import (
+	"context"
+
+	"github.com/gravitational/robotest/infra"
+	"github.com/gravitational/robotest/infra/autoscale"
 	"github.com/gravitational/robotest/infra/ops"
+	"github.com/gravitational/robotest/infra/terraform"
+	"github.com/gravitational/robotest/lib/constants"
 	"github.com/gravitational/robotest/lib/wait"
 	"github.com/gravitational/trace"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
-// AutoScale will update the autoscaling group to the target number of nodes,
+// AutoScale will update the scaling group to the target number of nodes,
 // and return a new list of nodes to be used for testing
 func (c *TestContext) AutoScale(target int) ([]Gravity, error) {
-	c.Logger().Debug("attempting to connect to AWS api")
-	sess, err := session.NewSession(&aws.Config{
-		Region:      aws.String(c.provisionerCfg.Ops.EC2Region),
-		Credentials: credentials.NewStaticCredentials(c.provisionerCfg.Ops.EC2AccessKey, c.provisionerCfg.Ops.EC2SecretKey, ""),
-	})
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
-	svc := autoscaling.New(sess)
-
-	// first, let's set the desired capacity
-	setCapacity := &autoscaling.SetDesiredCapacityInput{
-		AutoScalingGroupName: aws.String(c.provisionerCfg.clusterName),
-		DesiredCapacity:      aws.Int64(int64(target)),
-		HonorCooldown:        aws.Bool(false),
-	}
-	c.Logger().WithField("target_count", setCapacity).Debug("setting scaling group desired capacity")
-	_, err = svc.SetDesiredCapacity(setCapacity)
+	scaler, err := c.autoScaler()
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
-	// next we need to find all the instances that were just created, and build objects and ssh connections to them
-	describeASG := &autoscaling.DescribeAutoScalingGroupsInput{
-		AutoScalingGroupNames: []*string{
-			aws.String(c.provisionerCfg.clusterName),
-		},
-	}
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.AutoScaling)
+	defer cancel()
 
-	// we may need to wait for the nodes to get assigned by the autoscaling group
-	// so we need to repeat our API requests until we get the expected nodes
-	nodes := []Gravity{}
+	c.Logger().WithField("target_count", target).Debug("setting scaling group desired capacity")
 	retryer := wait.Retryer{
-		Delay:    autoscaleWait,
-		Attempts: autoscaleRetries,
+		Delay:       autoscaleWait,
+		Attempts:    autoscaleRetries,
+		FieldLogger: c.Logger(),
 	}
-
-	var result *autoscaling.DescribeAutoScalingGroupsOutput
-	err = retryer.Do(c.Context(), func() (err error) {
-		result, err = checkForNodeAssignment(svc, describeASG, target)
-		return trace.Wrap(err)
-	})
+	instances, err := autoscale.Scale(ctx, scaler, target, retryer)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
-	ec2svc := ec2.New(sess)
-	for _, instance := range result.AutoScalingGroups[0].Instances {
-		// attempt to get the actual instance for each instance-id in the cluster
-		node, err := c.getAWSNodes(ec2svc, "instance-id", *instance.InstanceId)
-		if err != nil {
-			return nil, trace.Wrap(err)
-		}
+	ctx, cancel = context.WithTimeout(c.parent, cloudInitTimeout)
+	defer cancel()
 
-		if len(node) != 1 {
-			return nil, trace.BadParameter("unexpected number of AWS nodes found. 1 != %v", len(nodes))
-		}
-		nodes = append(nodes, node[0])
+	return c.scaledNodes(ctx, instances)
+}
 
+// autoScaler returns scaling group of current cluster for the cloud provider used.
+// Ops Center names auto scaling group after the cluster,
+// otherwise scaling group is the one reported by terraform scripts
+func (c *TestContext) autoScaler() (autoscale.AutoScaler, error) {
+	cfg := c.provisionerCfg
+	if cfg.CloudProvider != constants.Ops && cfg.scalingGroup == "" {
+		return nil, trace.NotFound("terraform scripts at %v provide no scaling_group output", cfg.ScriptPath)
+	}
+	switch cfg.CloudProvider {
+	case constants.Ops:
+		return autoscale.NewAWS(autoscale.AWSConfig{
+			AccessKey: cfg.Ops.EC2AccessKey,
+			SecretKey: cfg.Ops.EC2SecretKey,
+			Region:    cfg.Ops.EC2Region,
+			GroupName: cfg.clusterName,
+		})
+	case constants.AWS:
+		return autoscale.NewAWS(autoscale.AWSConfig{
+			AccessKey: cfg.AWS.AccessKey,
+			SecretKey: cfg.AWS.SecretKey,
+			Region:    cfg.AWS.Region,
+			GroupName: cfg.scalingGroup,
+		})
+	case constants.Azure:
+		return autoscale.NewAzure(autoscale.AzureConfig{
+			AzureAuthParam: terraform.AzureAuthParam{
+				ClientId:     cfg.Azure.ClientId,
+				ClientSecret: cfg.Azure.ClientSecret,
+				TenantId:     cfg.Azure.TenantId,
+			},
+			SubscriptionId: cfg.Azure.SubscriptionId,
+			ResourceGroup:  cfg.Tag(),
+			ScaleSet:       cfg.scalingGroup,
+		})
+	default:
+		return nil, trace.BadParameter("autoscaling is not supported for %v", cfg.CloudProvider)
 	}
-
-	return nodes, nil
 }
 
-// checkForNodeAssignment will check to see if our auto-scaling group has the requested number of nodes assigned
-func checkForNodeAssignment(svc *autoscaling.AutoScaling, describeASG *autoscaling.DescribeAutoScalingGroupsInput, target int) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
-	result, err := svc.DescribeAutoScalingGroups(describeASG)
+// scaledNodes connects to instances of scaling group
+func (c *TestContext) scaledNodes(ctx context.Context, instances []autoscale.Instance) ([]Gravity, error) {
+	cloudParams, err := makeDynamicParams(c.provisionerCfg)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
-	if len(result.AutoScalingGroups) != 1 {
-		return nil, trace.BadParameter("unexpected number of autoscaling groups found: 1 != %v", len(result.AutoScalingGroups))
+	sshUser, sshKeyPath := sshCredentials(*cloudParams)
+	infraNodes := []infra.Node{}
+	for _, instance := range instances {
+		infraNodes = append(infraNodes, ops.New(instance.PublicAddr, instance.PrivateAddr, sshUser, sshKeyPath))
 	}
-	if len(result.AutoScalingGroups[0].Instances) != target {
-		return nil, trace.BadParameter("unexpected autoscaling count of instances. expected: %v got: %v", target, len(result.AutoScalingGroups[0].Instances))
+
+	nodes := []Gravity{}
+	for _, node := range c.withPowerControl(infraNodes, *cloudParams) {
+		gravityNode, err := configureVM(ctx, c.Logger(), node, *cloudParams)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		nodes = append(nodes, gravityNode)
 	}
-	return result, nil
+	return sorted(nodes), nil
 }
 
 // getAWSNodes will connect to the AWS API, and get a listing of nodes matching the specified filter.
 func (c *TestContext) getAWSNodes(ec2svc *ec2.EC2, filterName string, filterValue string) ([]Gravity, error) {
-	cloudParams, err := makeDynamicParams(c.provisionerCfg)
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
 	params := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			{
@@ -117,18 +122,19 @@ func (c *TestContext) getAWSNodes(ec2svc *ec2.EC2, filterName string, filterValu
 		return nil, trace.Wrap(err)
 	}
 
-	var nodes []Gravity
+	var instances []autoscale.Instance
 	for _, reservation := range resp.Reservations {
 		for _, inst := range reservation.Instances {
-			node := ops.New(*inst.PublicIpAddress, *inst.PrivateIpAddress, c.provisionerCfg.Ops.SSHUser, c.provisionerCfg.Ops.SSHKeyPath)
-
-			gravityNode, err := configureVM(c.Context(), c.Logger(), node, *cloudParams)
-			if err != nil {
-				return nil, trace.Wrap(err)
+			// terminated or stopped instances have no public address
+			if inst.State == nil || aws.StringValue(inst.State.Name) != ec2.InstanceStateNameRunning {
+				continue
 			}
-
-			nodes = append(nodes, gravityNode)
+			instances = append(instances, autoscale.Instance{
+				ID:          aws.StringValue(inst.InstanceId),
+				PublicAddr:  aws.StringValue(inst.PublicIpAddress),
+				PrivateAddr: aws.StringValue(inst.PrivateIpAddress),
+			})
 		}
 	}
-	return nodes, nil
+	return c.scaledNodes(c.Context(), instances)
 }
@@ -0,0 +1,137 @@
+package gravity
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/gravitational/robotest/lib/defaults"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// backupFile is where cluster application backup is created on the node
+	backupFile = path.Join(defaults.TmpDir, "robotest-backup.tar.gz")
+	// restoredBackupFile is where backup of restored cluster application is created on the node
+	restoredBackupFile = path.Join(defaults.TmpDir, "robotest-restored.tar.gz")
+)
+
+// Backup is cluster application backup created on the node
+type Backup struct {
+	// File is backup file path on the node to restore from
+	File string
+	// Entries are files in backup tarball, as written by application backup hooks
+	Entries []string
+}
+
+// Backup creates cluster application backup on the node, and collects backup tarball
+// into state dir as test artifact. Backup containing no files is an error
+func (c *TestContext) Backup(node Gravity) (*Backup, error) {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Backup)
+	defer cancel()
+
+	return c.backup(ctx, node, backupFile)
+}
+
+// Restore restores cluster application from backup file on the node
+func (c *TestContext) Restore(node Gravity, backup *Backup) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Backup)
+	defer cancel()
+
+	return trace.Wrap(node.Restore(ctx, backup.File))
+}
+
+// VerifyRestore verifies application managed state has been restored:
+// backup of restored cluster application should contain the same files as the original backup
+func (c *TestContext) VerifyRestore(node Gravity, backup *Backup) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Backup)
+	defer cancel()
+
+	restored, err := c.backup(ctx, node, restoredBackupFile)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if err := compareBackupEntries(backup.Entries, restored.Entries); err != nil {
+		return trace.Wrap(err)
+	}
+
+	c.Logger().WithFields(logrus.Fields{"node": node, "entries": len(restored.Entries)}).Info("application state restored")
+	return nil
+}
+
+func (c *TestContext) backup(ctx context.Context, node Gravity, file string) (*Backup, error) {
+	localPath, err := node.Backup(ctx, file)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	entries, err := backupEntries(localPath)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	c.Logger().WithFields(logrus.Fields{"node": node, "backup": localPath, "entries": len(entries)}).Info("backup collected")
+	return &Backup{File: file, Entries: entries}, nil
+}
+
+// backupEntries returns sorted names of regular files in gzipped backup tarball,
+// it fails if there are none
+func backupEntries(localPath string) ([]string, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, trace.Wrap(err, "backup %v", localPath)
+	}
+	defer gz.Close()
+
+	var entries []string
+	r := tar.NewReader(gz)
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, trace.Wrap(err, "backup %v", localPath)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			entries = append(entries, path.Clean(hdr.Name))
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, trace.BadParameter("backup %v contains no files", localPath)
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
+
+func compareBackupEntries(expected, actual []string) error {
+	have := make(map[string]bool, len(actual))
+	for _, entry := range actual {
+		have[entry] = true
+	}
+
+	var missing []string
+	for _, entry := range expected {
+		if !have[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) != 0 {
+		return trace.CompareFailed("restored application state misses %v", missing)
+	}
+	return nil
+}
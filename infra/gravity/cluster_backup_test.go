@@ -0,0 +1,54 @@
+package gravity
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "robotest-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	backup := writeBackup(t, dir, "backup.tar.gz", "db/dump.sql", "./config.json")
+	entries, err := backupEntries(backup)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config.json", "db/dump.sql"}, entries)
+
+	empty := writeBackup(t, dir, "empty.tar.gz")
+	_, err = backupEntries(empty)
+	assert.True(t, trace.IsBadParameter(err), "empty backup: %v", err)
+
+	assert.NoError(t, compareBackupEntries(entries, []string{"config.json", "db/dump.sql", "extra"}))
+	err = compareBackupEntries(entries, []string{"config.json"})
+	assert.True(t, trace.IsCompareFailed(err), "missing entry: %v", err)
+}
+
+// writeBackup writes gzipped tarball with a directory and given files
+func writeBackup(t *testing.T, dir, name string, files ...string) string {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	w := tar.NewWriter(gz)
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: "db/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, file := range files {
+		data := []byte("backup")
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: file, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))}))
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return path
+}
@@ -0,0 +1,236 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// clusterDNSName is resolved from every node to verify cluster DNS
+const clusterDNSName = "kubernetes.default.svc.cluster.local"
+
+// NodeHealth is health of a cluster node as observed from the node itself
+type NodeHealth struct {
+	// Node is node private and public address
+	Node string `json:"node"`
+	// Status is cluster status reported by `gravity status`, empty if not available
+	Status string `json:"status"`
+	// KubeReady is whether Kubernetes reports node as Ready
+	KubeReady bool `json:"kube_ready"`
+	// PodsNotReady are kube-system pods running on the node which are not ready
+	PodsNotReady []string `json:"pods_not_ready,omitempty"`
+	// EtcdHealthy is whether etcd cluster is healthy as observed from the node
+	EtcdHealthy bool `json:"etcd_healthy"`
+	// DNS is whether cluster DNS names resolve on the node
+	DNS bool `json:"dns"`
+	// Errors are failures to run checks
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Problems returns human readable list of failed checks
+func (h NodeHealth) Problems() []string {
+	problems := []string{}
+	if h.Status != StatusActive {
+		problems = append(problems, fmt.Sprintf("status %q", h.Status))
+	}
+	if !h.KubeReady {
+		problems = append(problems, "kubernetes node not ready")
+	}
+	if len(h.PodsNotReady) != 0 {
+		problems = append(problems, fmt.Sprintf("pods not ready: %v", strings.Join(h.PodsNotReady, ",")))
+	}
+	if !h.EtcdHealthy {
+		problems = append(problems, "etcd not healthy")
+	}
+	if !h.DNS {
+		problems = append(problems, "DNS not resolving")
+	}
+	return problems
+}
+
+// HealthReport is cluster health per node
+type HealthReport struct {
+	// Timestamp is when report was collected
+	Timestamp time.Time `json:"timestamp"`
+	// Nodes is health of individual nodes
+	Nodes []NodeHealth `json:"nodes"`
+}
+
+// Healthy returns true when all checks passed on all nodes
+func (r HealthReport) Healthy() bool {
+	return len(r.Problems()) == 0
+}
+
+// Problems returns failed checks prefixed by node
+func (r HealthReport) Problems() []string {
+	problems := []string{}
+	for _, node := range r.Nodes {
+		for _, problem := range node.Problems() {
+			problems = append(problems, fmt.Sprintf("%v: %v", node.Node, problem))
+		}
+	}
+	return problems
+}
+
+// ClusterHealth collects health report from all nodes.
+// Individual checks failures are recorded in report rather than returned as error
+func (c *TestContext) ClusterHealth(nodes []Gravity) (*HealthReport, error) {
+	if len(nodes) == 0 {
+		return nil, trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	report := collectHealth(ctx, nodes)
+	c.setHealth(report)
+	return report, nil
+}
+
+// CheckHealth waits until all health checks pass on all nodes,
+// and could be used as a checkpoint within the test
+func (c *TestContext) CheckHealth(nodes []Gravity) error {
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       time.Second * 20,
+		FieldLogger: c.Logger().WithField("checkpoint", "health"),
+	}
+
+	var report *HealthReport
+	err := retry.Do(ctx, func() error {
+		report = collectHealth(ctx, nodes)
+		c.setHealth(report)
+		if problems := report.Problems(); len(problems) != 0 {
+			return wait.Continue(strings.Join(problems, "; "))
+		}
+		return nil
+	})
+	if err != nil && report == nil {
+		return trace.Wrap(err)
+	}
+	if err != nil {
+		return trace.CompareFailed("cluster not healthy: %v", strings.Join(report.Problems(), "; "))
+	}
+
+	c.Logger().WithField("health", report).Info("cluster healthy")
+	return nil
+}
+
+// Health returns last collected cluster health report, or nil
+func (c *TestContext) Health() *HealthReport {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	return c.health
+}
+
+func (c *TestContext) setHealth(report *HealthReport) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.health = report
+}
+
+// collectHealth runs checks on every node in parallel,
+// cluster wide Kubernetes state is queried from the first available node
+func collectHealth(ctx context.Context, nodes []Gravity) *HealthReport {
+	report := &HealthReport{Timestamp: time.Now(), Nodes: make([]NodeHealth, len(nodes))}
+
+	var kubeNodes []KubeNode
+	var pods []Pod
+	var kubeErr error
+	for _, node := range nodes {
+		if node.Offline() {
+			continue
+		}
+		kubeNodes, kubeErr = KubectlGetNodes(ctx, node)
+		if kubeErr != nil {
+			continue
+		}
+		pods, kubeErr = KubectlGetPods(ctx, node, kubeSystemNS, "")
+		if kubeErr == nil {
+			break
+		}
+	}
+
+	done := make(chan struct{}, len(nodes))
+	for i, node := range nodes {
+		go func(health *NodeHealth, node Gravity) {
+			defer func() { done <- struct{}{} }()
+			*health = nodeHealth(ctx, node, kubeNodes, pods)
+			if kubeErr != nil {
+				health.Errors = append(health.Errors, fmt.Sprintf("kubectl: %v", kubeErr))
+			}
+		}(&report.Nodes[i], node)
+	}
+	for range nodes {
+		<-done
+	}
+
+	return report
+}
+
+func nodeHealth(ctx context.Context, node Gravity, kubeNodes []KubeNode, pods []Pod) NodeHealth {
+	health := NodeHealth{Node: node.String()}
+	addr := node.Node().PrivateAddr()
+
+	for _, kubeNode := range kubeNodes {
+		if kubeNode.IP == addr {
+			health.KubeReady = kubeNode.Ready
+		}
+	}
+	for _, pod := range pods {
+		if pod.NodeIP == addr && !pod.Ready && pod.Phase != "Succeeded" {
+			health.PodsNotReady = append(health.PodsNotReady, pod.Name)
+		}
+	}
+
+	if node.Offline() {
+		health.Errors = append(health.Errors, "node is offline")
+		return health
+	}
+
+	status, err := node.Status(ctx)
+	if err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("status: %v", trace.UserMessage(err)))
+	} else {
+		health.Status = status.Status
+	}
+
+	if err = waitEtcdHealthOk(ctx, node)(); err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("etcd: %v", trace.UserMessage(err)))
+	} else {
+		health.EtcdHealthy = true
+	}
+
+	if _, err = ResolveInPlanet(ctx, node, clusterDNSName); err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("dns: %v", trace.UserMessage(err)))
+	} else {
+		health.DNS = true
+	}
+
+	return health
+}
+
+// logHealth collects and logs cluster health, used upon test failure
+func (c *TestContext) logHealth(nodes []Gravity) {
+	report, err := c.ClusterHealth(nodes)
+	if err != nil {
+		c.Logger().WithError(err).Error("collecting cluster health")
+		return
+	}
+	c.Logger().WithFields(logrus.Fields{
+		"health": report, "problems": report.Problems(),
+	}).Warn("cluster health on failure")
+}
@@ -0,0 +1,34 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthReport(t *testing.T) {
+	healthy := NodeHealth{Node: "10.0.0.1", Status: StatusActive, KubeReady: true, EtcdHealthy: true, DNS: true}
+	report := HealthReport{Nodes: []NodeHealth{healthy}}
+	assert.True(t, report.Healthy())
+	assert.Empty(t, report.Problems())
+
+	degraded := NodeHealth{Node: "10.0.0.2", Status: StatusDegraded, KubeReady: true,
+		PodsNotReady: []string{"kube-dns-1", "gravity-site-2"}, DNS: true}
+	report.Nodes = append(report.Nodes, degraded)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, []string{
+		`10.0.0.2: status "degraded"`,
+		"10.0.0.2: pods not ready: kube-dns-1,gravity-site-2",
+		"10.0.0.2: etcd not healthy",
+	}, report.Problems())
+}
+
+func TestParseKubeNodes(t *testing.T) {
+	nodes, err := parseKubeNodes("10.0.0.1,True\n10.0.0.2,False\r\n\n")
+	require.NoError(t, err)
+	assert.Equal(t, []KubeNode{{IP: "10.0.0.1", Ready: true}, {IP: "10.0.0.2", Ready: false}}, nodes)
+
+	_, err = parseKubeNodes("error: the server doesn't have a resource type")
+	assert.Error(t, err)
+}
@@ -205,6 +205,58 @@ func (c *TestContext) Upgrade(nodes []Gravity, installerUrl, subdir string) erro
 	return trace.Wrap(err)
 }
 
+// OfflineUpdate transfers installer to the API master node and updates cluster application
+// from it with `gravity update`, without Ops Center, and verifies application version has changed
+func (c *TestContext) OfflineUpdate(nodes []Gravity, installerUrl string) error {
+	roles, err := c.NodesByRole(nodes)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	master := roles.ApiMaster
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	before, err := master.Status(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Install, len(nodes)))
+	defer cancel()
+
+	err = master.SetInstaller(ctx, installerUrl, "update")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Upgrade, len(nodes)))
+	defer cancel()
+
+	err = master.OfflineUpdate(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	after, err := master.Status(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if after.AppVersion == "" {
+		return trace.NotFound("application version not reported, could not verify update")
+	}
+	if after.AppVersion == before.AppVersion {
+		return trace.CompareFailed("application version is still %q after update", after.AppVersion)
+	}
+
+	c.Logger().WithFields(log.Fields{"from": before.AppVersion, "to": after.AppVersion}).Info("application updated")
+	return nil
+}
+
 // ExecScript will run and execute a script on all nodes
 func (c *TestContext) ExecScript(nodes []Gravity, scriptUrl string, args []string) error {
 	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
@@ -0,0 +1,73 @@
+package gravity
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+// CheckNetworkCIDRs verifies pods and services were allocated IPs
+// from pod network and service CIDRs requested on install, if any
+func (c *TestContext) CheckNetworkCIDRs(nodes []Gravity, param InstallParam) error {
+	if param.PodNetworkCIDR == "" && param.ServiceCIDR == "" {
+		return nil
+	}
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	master := nodes[0]
+	var errors []error
+	if param.PodNetworkCIDR != "" {
+		out, err := master.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "pods", "--all-namespaces",
+			`-ojsonpath='{range .items[?(@.spec.hostNetwork!=true)]}{.status.podIP}{"\n"}{end}'`)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		errors = append(errors, trace.Wrap(checkCIDR(param.PodNetworkCIDR, strings.Fields(out)), "pod IPs"))
+	}
+	if param.ServiceCIDR != "" {
+		out, err := master.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "services", "--all-namespaces",
+			`-ojsonpath='{.items[*].spec.clusterIP}'`)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		errors = append(errors, trace.Wrap(checkCIDR(param.ServiceCIDR, strings.Fields(out)), "service IPs"))
+	}
+
+	return trace.NewAggregate(errors...)
+}
+
+// checkCIDR verifies all IPs belong to CIDR, ignoring anything which is not an IP,
+// i.e. "None" cluster IP of headless services or sudo warnings
+func checkCIDR(cidr string, ips []string) error {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return trace.BadParameter("invalid CIDR %q: %v", cidr, err)
+	}
+
+	found, outside := 0, []string{}
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			continue
+		}
+		found++
+		if !ipNet.Contains(ip) {
+			outside = append(outside, s)
+		}
+	}
+
+	if found == 0 {
+		return trace.NotFound("no IPs to verify against %v", cidr)
+	}
+	if len(outside) != 0 {
+		return trace.CompareFailed("%v outside of %v", strings.Join(outside, ","), cidr)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCIDR(t *testing.T) {
+	assert.NoError(t, checkCIDR("10.200.0.0/16", []string{"10.200.0.1", "None", "10.200.255.10"}))
+
+	err := checkCIDR("10.200.0.0/16", []string{"10.200.0.1", "10.100.0.1"})
+	assert.True(t, trace.IsCompareFailed(err), "%v", err)
+	assert.Contains(t, err.Error(), "10.100.0.1")
+
+	err = checkCIDR("10.200.0.0/16", []string{"sudo:", "None"})
+	assert.True(t, trace.IsNotFound(err), "%v", err)
+
+	err = checkCIDR("10.200.0.0", nil)
+	assert.True(t, trace.IsBadParameter(err), "%v", err)
+}
@@ -0,0 +1,167 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gravitational/robotest/infra"
+	"github.com/gravitational/robotest/infra/autoscale"
+	"github.com/gravitational/robotest/infra/ops"
+	"github.com/gravitational/robotest/lib/constants"
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// ExpandOps adds count nodes to ops provisioned cluster and returns newly added nodes.
+// Ops Center provisions AWS clusters with an auto scaling group named after the cluster,
+// and instances launched by the group are joined by the cluster autoscaler
+func (c *TestContext) ExpandOps(current []Gravity, count int) ([]Gravity, error) {
+	if len(current) == 0 || count <= 0 {
+		return nil, trace.BadParameter("expected existing nodes and positive count, got %v nodes and count %v",
+			len(current), count)
+	}
+	scaler, err := c.opsScaler()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, withDuration(c.timeouts.Install, count))
+	defer cancel()
+
+	target := len(current) + count
+	log := c.Logger().WithFields(logrus.Fields{"cluster": c.provisionerCfg.clusterName, "target": target})
+	log.Info("scaling up Ops Center cluster")
+
+	retry := wait.Retryer{
+		Delay:       autoscaleWait,
+		Attempts:    autoscaleRetries,
+		FieldLogger: log,
+	}
+	instances, err := autoscale.Scale(ctx, scaler, target, retry)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	existing := map[string]bool{}
+	for _, node := range current {
+		existing[node.Node().PrivateAddr()] = true
+	}
+	var launched []autoscale.Instance
+	for _, instance := range instances {
+		if !existing[instance.PrivateAddr] {
+			launched = append(launched, instance)
+		}
+	}
+	if len(launched) != count {
+		return nil, trace.CompareFailed("expected %v new instances, found %v", count, len(launched))
+	}
+
+	added, err := c.scaledNodes(ctx, launched)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, node := range added {
+		go node.(*gravity).streamLogs(c.Context())
+	}
+
+	err = waitClusterNodes(ctx, current[0], added, true, log)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return added, nil
+}
+
+// ShrinkOps removes nodes from ops provisioned cluster, one at a time,
+// by terminating their instances in the auto scaling group of the cluster,
+// and waits until the cluster autoscaler removes them from the cluster
+func (c *TestContext) ShrinkOps(nodesToKeep, nodesToRemove []Gravity) error {
+	if len(nodesToKeep) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+	scaler, err := c.opsScaler()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, withDuration(c.timeouts.Leave, len(nodesToRemove)))
+	defer cancel()
+
+	instances, err := scaler.ListInstances(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	byAddr := map[string]string{}
+	for _, instance := range instances {
+		byAddr[instance.PrivateAddr] = instance.ID
+	}
+
+	for _, node := range nodesToRemove {
+		log := c.Logger().WithFields(logrus.Fields{"cluster": c.provisionerCfg.clusterName, "node": node.String()})
+
+		id, ok := byAddr[node.Node().PrivateAddr()]
+		if !ok {
+			return trace.NotFound("no instance with address %v in scaling group", node.Node().PrivateAddr())
+		}
+
+		log.WithField("instance", id).Info("terminating Ops Center cluster instance")
+		err = scaler.Remove(ctx, id)
+		if err != nil {
+			return trace.Wrap(err, "removing %v", node)
+		}
+
+		err = waitClusterNodes(ctx, nodesToKeep[0], []Gravity{node}, false, log)
+		if err != nil {
+			return trace.Wrap(err, "removing %v", node)
+		}
+	}
+
+	return nil
+}
+
+// waitClusterNodes polls cluster status from master until nodes are either all present, or all absent
+func waitClusterNodes(ctx context.Context, master Gravity, nodes []Gravity, present bool, log logrus.FieldLogger) error {
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       opsNodesWait,
+		FieldLogger: log.WithField("present", present),
+	}
+
+	err := retry.Do(ctx, func() error {
+		status, err := master.Status(ctx)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+
+		observed := map[string]bool{}
+		for _, addr := range status.Nodes {
+			observed[addr] = true
+		}
+		for _, node := range nodes {
+			addr := node.Node().PrivateAddr()
+			if observed[addr] != present {
+				return wait.Continue(fmt.Sprintf("node %v present in cluster: %v", addr, observed[addr]))
+			}
+		}
+		return nil
+	})
+	return trace.Wrap(err)
+}
+
+// opsScaler returns auto scaling group of ops provisioned cluster
+func (c *TestContext) opsScaler() (autoscale.AutoScaler, error) {
+	if c.provisionerCfg.CloudProvider != constants.Ops {
+		return nil, trace.BadParameter("cluster was not provisioned via Ops Center")
+	}
+	return c.autoScaler()
+}
+
+// opsLogin logs into Ops Center with `tele`
+func opsLogin(ctx context.Context, cfg *infra.OpsConfig) (ops.Operator, error) {
+	tele, err := ops.TeleLogin(ctx, cfg.URL, cfg.OpsKey)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return tele, nil
+}
@@ -0,0 +1,27 @@
+package gravity
+
+import (
+	"context"
+
+	"github.com/gravitational/robotest/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Reboot restarts nodes simultaneously and waits until they are accessible again
+func (c *TestContext) Reboot(nodes []Gravity, graceful Graceful) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Reboot)
+	defer cancel()
+
+	c.Logger().WithFields(logrus.Fields{"nodes": nodes, "graceful": graceful}).Info("reboot")
+
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(n Gravity) {
+			errs <- trace.Wrap(n.Reboot(ctx, graceful), n.String())
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
@@ -2,7 +2,10 @@ package gravity
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/gravitational/robotest/lib/constants"
 	sshutils "github.com/gravitational/robotest/lib/ssh"
 	"github.com/gravitational/robotest/lib/utils"
 	"github.com/gravitational/robotest/lib/wait"
@@ -10,12 +13,14 @@ import (
 	"github.com/gravitational/trace"
 )
 
+// Expand joins extra nodes to the cluster of current nodes, one by one or concurrently,
+// using cluster join token unless another token is given in p. Time it took is recorded
 func (c *TestContext) Expand(current, extra []Gravity, p InstallParam) error {
 	if len(current) == 0 || len(extra) == 0 {
 		return trace.Errorf("empty node list")
 	}
-	if c.provisionerCfg.CloudProvider == "ops" {
-		return trace.Errorf("not implemented")
+	if c.provisionerCfg.CloudProvider == constants.Ops {
+		return trace.BadParameter("nodes of ops provisioned cluster are added with ExpandOps")
 	}
 
 	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
@@ -28,24 +33,66 @@ func (c *TestContext) Expand(current, extra []Gravity, p InstallParam) error {
 		return trace.Wrap(err, "query status from [%v]", master)
 	}
 
-	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Install, len(extra)))
-	defer cancel()
+	token := status.Token
+	if p.Token != "" {
+		token = p.Token
+	}
 
-	for _, node := range extra {
-		err = node.Join(ctx, JoinCmd{
+	joinCmd := func(i int) JoinCmd {
+		return JoinCmd{
 			PeerAddr: joinAddr,
-			Token:    status.Token,
-			Role:     p.Role,
+			Token:    token,
+			Role:     joinRole(p, i),
 			StateDir: p.StateDir,
-		})
+		}
+	}
+
+	start := time.Now()
+	if p.ExpandParallel {
+		ctx, cancel = context.WithTimeout(c.parent, c.timeouts.Install)
+		defer cancel()
+
+		errs := make(chan error, len(extra))
+		for i, node := range extra {
+			go func(n Gravity, cmd JoinCmd) {
+				err := n.Join(ctx, cmd)
+				if err != nil {
+					n.Logger().WithError(err).Error("join failed")
+				}
+				errs <- trace.Wrap(err, n.String())
+			}(node, joinCmd(i))
+		}
+
+		_, err = utils.Collect(ctx, cancel, errs, nil)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		c.RecordTiming(fmt.Sprintf("join %d nodes concurrently", len(extra)), time.Since(start))
+		return nil
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Install, len(extra)))
+	defer cancel()
+
+	for i, node := range extra {
+		err = node.Join(ctx, joinCmd(i))
 		if err != nil {
 			return trace.Wrap(err, "error joining cluster on node %s: %v", node.String(), err)
 		}
 	}
 
+	c.RecordTiming(fmt.Sprintf("join %d nodes one by one", len(extra)), time.Since(start))
 	return nil
 }
 
+// joinRole returns role of i-th node joining on expand
+func joinRole(p InstallParam, i int) string {
+	if i < len(p.ExpandRoles) && p.ExpandRoles[i] != "" {
+		return p.ExpandRoles[i]
+	}
+	return p.Role
+}
+
 func waitEtcdHealthOk(ctx context.Context, node Gravity) func() error {
 	return func() error {
 		exitCode, err := sshutils.RunAndParse(ctx, node.Client(), node.Logger(),
@@ -65,6 +112,10 @@ func waitEtcdHealthOk(ctx context.Context, node Gravity) func() error {
 
 // ShrinkLeave will gracefully leave cluster
 func (c *TestContext) ShrinkLeave(nodesToKeep, nodesToRemove []Gravity) error {
+	if c.provisionerCfg.CloudProvider == constants.Ops {
+		return trace.Wrap(c.ShrinkOps(nodesToKeep, nodesToRemove))
+	}
+
 	ctx, cancel := context.WithTimeout(c.parent, withDuration(c.timeouts.Leave, len(nodesToRemove)))
 	defer cancel()
 
@@ -85,6 +136,14 @@ func (c *TestContext) RemoveNode(nodesToKeep []Gravity, remove Gravity) error {
 		return trace.BadParameter("node list empty")
 	}
 
+	if c.provisionerCfg.CloudProvider == constants.Ops {
+		err := c.ShrinkOps(nodesToKeep, []Gravity{remove})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		return trace.Wrap(c.Status(nodesToKeep))
+	}
+
 	master := nodesToKeep[0]
 
 	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Leave)
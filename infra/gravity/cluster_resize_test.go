@@ -0,0 +1,19 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinRole(t *testing.T) {
+	p := InstallParam{Role: "node", ExpandRoles: []string{"master", "", "db"}}
+
+	var roles []string
+	for i := 0; i < 5; i++ {
+		roles = append(roles, joinRole(p, i))
+	}
+	assert.Equal(t, []string{"master", "node", "db", "node", "node"}, roles)
+
+	assert.Equal(t, "node", joinRole(InstallParam{Role: "node"}, 0), "no expand roles")
+}
@@ -0,0 +1,136 @@
+package gravity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// UpgradeRollback launches upgrade in manual mode and executes its phases one by one
+// until failPhase, which is interrupted to simulate failure. Executed phases are then
+// rolled back in reverse order, and cluster is verified to be back to the original version and healthy
+func (c *TestContext) UpgradeRollback(nodes []Gravity, installerUrl, subdir, failPhase string) error {
+	roles, err := c.NodesByRole(nodes)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	master := roles.ApiMaster
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	before, err := master.Status(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Install, len(nodes)))
+	defer cancel()
+
+	err = master.SetInstaller(ctx, installerUrl, subdir)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = master.Upload(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, withDuration(c.timeouts.Upgrade, len(nodes)))
+	defer cancel()
+
+	err = master.UpgradeManual(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = executeUntil(ctx, master, failPhase, c.Logger())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = rollbackPlan(ctx, master, c.Logger())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = c.CheckHealth(nodes)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ctx, cancel = context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	after, err := master.Status(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if after.AppVersion != before.AppVersion {
+		return trace.CompareFailed("application version %q after rollback, expected %q",
+			after.AppVersion, before.AppVersion)
+	}
+	return nil
+}
+
+// executeUntil executes top level phases of the operation plan in order,
+// and interrupts failPhase once it is reached
+func executeUntil(ctx context.Context, master Gravity, failPhase string, log logrus.FieldLogger) error {
+	phases, err := master.Plan(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ids := []string{}
+	found := false
+	for _, phase := range phases {
+		ids = append(ids, phase.ID)
+		found = found || phase.ID == failPhase
+	}
+	if !found {
+		return trace.NotFound("no phase %q in operation plan, available phases are %v",
+			failPhase, strings.Join(ids, ","))
+	}
+
+	for _, phase := range phases {
+		if phase.ID == failPhase {
+			break
+		}
+		log.WithField("phase", phase.ID).Info(phase.Description)
+		err = master.ExecutePhase(ctx, phase.ID)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return trace.Wrap(master.InterruptPhase(ctx, failPhase, phaseInterruptDelay))
+}
+
+// rollbackPlan rolls back phases of the operation plan which were started,
+// in reverse order, and completes the operation.
+// This is what `gravity plan rollback` without a phase does in recent versions,
+// while older ones only roll back a phase given with --phase,
+// so rolling back phase by phase works the same across supported versions
+func rollbackPlan(ctx context.Context, master Gravity, log logrus.FieldLogger) error {
+	phases, err := master.Plan(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	for i := len(phases) - 1; i >= 0; i-- {
+		phase := phases[i]
+		if phase.State == PhaseUnstarted || phase.State == PhaseRolledBack {
+			continue
+		}
+		log.WithFields(logrus.Fields{"phase": phase.ID, "state": phase.State}).Info("rollback phase")
+		err = master.RollbackPhase(ctx, phase.ID)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
+	return trace.Wrap(master.CompletePlan(ctx))
+}
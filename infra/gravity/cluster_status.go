@@ -2,6 +2,7 @@ package gravity
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	sshutils "github.com/gravitational/robotest/lib/ssh"
@@ -42,11 +43,46 @@ func (c *TestContext) Status(nodes []Gravity) error {
 	return trace.Wrap(err)
 }
 
+// WaitStatus waits until every node reports expected cluster status, i.e. StatusDegraded
+func (c *TestContext) WaitStatus(nodes []Gravity, expected string) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       time.Second * 20,
+		FieldLogger: c.Logger().WithField("expected_status", expected),
+	}
+
+	err := retry.Do(ctx, func() error {
+		for _, node := range nodes {
+			status, err := node.Status(ctx)
+			if err != nil {
+				return wait.Continue(fmt.Sprintf("status not available on %v: %v", node, err))
+			}
+			if status.Status != expected {
+				return wait.Continue(fmt.Sprintf("%v reports %q", node, status.Status))
+			}
+		}
+		return nil
+	})
+
+	return trace.Wrap(err)
+}
+
+// EtcdHealth waits until etcd cluster is healthy as observed from the node
+func (c *TestContext) EtcdHealth(node Gravity) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	return trace.Wrap(wait.Retry(ctx, waitEtcdHealthOk(ctx, node)))
+}
+
 // CheckTime walks around all nodes and checks whether their time is within acceptable limits
 func (c *TestContext) CheckTimeSync(nodes []Gravity) error {
 	timeNodes := []sshutils.SshNode{}
-	for _, n := range timeNodes {
-		timeNodes = append(timeNodes, n)
+	for _, n := range nodes {
+		timeNodes = append(timeNodes, sshutils.SshNode{n.Client(), n.Logger()})
 	}
 
 	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
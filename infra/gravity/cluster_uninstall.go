@@ -0,0 +1,81 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// hostCheck is a command which prints leftovers of gravity installation on the host, if any
+type hostCheck struct {
+	name string
+	cmd  string
+}
+
+// cleanHostChecks verify nothing is left on the host after uninstall,
+// {{.StateDir}} is replaced with gravity state dir
+var cleanHostChecks = []hostCheck{
+	{"processes", `ps -e -o comm= | grep -E '^(gravity|planet|teleport|etcd|kubelet|kube-apiserver|kube-proxy)$'`},
+	{"mounts", `awk '$2 ~ "^{{.StateDir}}/" || $2 ~ "planet" {print $2}' /proc/mounts`},
+	{"systemd units", `systemctl list-units --all --plain --no-legend | awk '{print $1}' | grep -E 'gravity|planet|teleport'; ` +
+		`ls /etc/systemd/system /lib/systemd/system 2>/dev/null | grep -E 'gravity|planet|teleport'`},
+	{"state dir", `sudo find {{.StateDir}} -mindepth 1 -maxdepth 1 -not -name lost+found 2>/dev/null`},
+	{"iptables rules", `sudo iptables-save | grep -E 'KUBE-|CNI-|flannel|cni0'`},
+	{"loop devices", `sudo losetup -a | grep -E 'gravity|planet'`},
+	{"users", `getent passwd planet; getent group planet`},
+}
+
+// CheckClean verifies hosts are clean after uninstall: no gravity processes, mounts,
+// systemd units, files in stateDir, iptables rules, loop devices or users are left behind
+func (c *TestContext) CheckClean(nodes []Gravity, stateDir string) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Uninstall)
+	defer cancel()
+
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(n Gravity) {
+			errs <- trace.Wrap(checkClean(ctx, n, stateDir), n.String())
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
+
+func checkClean(ctx context.Context, node Gravity, stateDir string) error {
+	leftovers := []string{}
+	for _, check := range cleanHostChecks {
+		var out string
+		cmd := strings.Replace(check.cmd, "{{.StateDir}}", stateDir, -1)
+		_, err := sshutils.RunAndParse(ctx, node.Client(), node.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+		if err != nil {
+			return trace.Wrap(err, cmd)
+		}
+
+		if lines := nonEmptyLines(out); len(lines) != 0 {
+			leftovers = append(leftovers, fmt.Sprintf("%v: %v", check.name, strings.Join(lines, ",")))
+		}
+	}
+
+	if len(leftovers) != 0 {
+		return trace.CompareFailed("host not clean after uninstall: %v", strings.Join(leftovers, "; "))
+	}
+	return nil
+}
+
+// nonEmptyLines returns non-empty lines of command output, skipping sudo warnings
+func nonEmptyLines(out string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "sudo:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -84,6 +84,8 @@ type ProvisionerConfig struct {
 	InstallerURL string `yaml:"installer_url" validate:"required,url`
 	// StateDir defines base directory where to keep state (i.e. terraform configs/vars)
 	StateDir string `yaml:"state_dir" validate:"required"`
+	// FixTimeSync defines whether to set clocks explicitly when nodes fail to synchronize time after provisioning
+	FixTimeSync bool `yaml:"fix_time_sync"`
 
 	// Tag will group provisioned resources under for easy removal afterwards
 	tag string `validate:"required"`
@@ -97,6 +99,8 @@ type ProvisionerConfig struct {
 	dockerDevice string `validate:"required"`
 	// clusterName is the name of the cluster / auto-scaling group / etc
 	clusterName string
+	// scalingGroup is the name of auto scaling group / VM scale set reported by provisioner
+	scalingGroup string
 }
 
 // LoadConfig loads essential parameters from YAML
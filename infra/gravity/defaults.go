@@ -20,6 +20,16 @@ const (
 	autoscaleRetries = 20               // total number of attempts when checking autoscale changes
 	autoscaleWait    = time.Second * 15 // amount of time to wait between attempts to autoscale the cluster
 
+	opsNodesWait = time.Second * 20 // amount of time to wait between checks of nodes in Ops Center cluster
+
+	componentRecoveryWait = time.Second * 5 // amount of time to wait between checks of killed component
+
+	diskSpeedWait = time.Second * 5 // amount of time to wait between disk throughput measurements
+
+	probeCheckTimeout = time.Minute // abort availability check if probing node does not respond
+
+	phaseInterruptDelay = time.Second * 10 // amount of time operation phase runs before it is interrupted
+
 	// minimum required disk speed (10MB/s)
 	minDiskSpeed = uint64(1e7)
 )
@@ -33,4 +43,7 @@ var DefaultTimeouts = OpTimeouts{
 	CollectLogs:      time.Minute * 7,  // to collect logs from node
 	WaitForInstaller: time.Minute * 30, // wait for build to complete in parallel
 	AutoScaling:      time.Minute * 10, // wait for autoscaling operation
+	Fault:            time.Minute * 5,  // inject or remove a fault on all nodes
+	Reboot:           time.Minute * 15, // reboot node and reconnect to it
+	Backup:           time.Minute * 20, // backup or restore cluster application
 }
@@ -0,0 +1,44 @@
+package gravity
+
+import (
+	"context"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// SkewClock stops time synchronization on the node and shifts its clock by offset.
+// Time synchronization is restored with RestoreClocks, or on teardown
+func (c *TestContext) SkewClock(node Gravity, offset time.Duration) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	c.Logger().WithFields(logrus.Fields{"node": node, "offset": offset.String()}).Warn("FAULT: clock skew")
+	c.OnTeardown("restore clock on "+node.String(), func(ctx context.Context) error {
+		if node.Offline() {
+			return nil
+		}
+		return trace.Wrap(sshutils.RestoreTimeSync(ctx, sshutils.SshNode{node.Client(), node.Logger()}))
+	})
+
+	err := sshutils.StopTimeSync(ctx, sshutils.SshNode{node.Client(), node.Logger()}, time.Now().Add(offset))
+	return trace.Wrap(err)
+}
+
+// RestoreClocks sets clocks on nodes back to actual time, restarts time synchronization
+// and verifies clocks are in sync
+func (c *TestContext) RestoreClocks(nodes []Gravity) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	c.Logger().WithField("nodes", nodes).Info("restore clocks")
+	timeNodes := []sshutils.SshNode{}
+	for _, node := range nodes {
+		timeNodes = append(timeNodes, sshutils.SshNode{node.Client(), node.Logger()})
+	}
+
+	return trace.Wrap(sshutils.FixTimeSync(ctx, timeNodes))
+}
@@ -0,0 +1,194 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// RestoreFn reverts fault previously injected on the node
+type RestoreFn func(ctx context.Context) error
+
+// Once returns restore function which reverts the fault on the first call only,
+// so that it may be both invoked by the test and registered for teardown
+func (r RestoreFn) Once() RestoreFn {
+	var once sync.Once
+	var err error
+	return func(ctx context.Context) error {
+		once.Do(func() { err = r(ctx) })
+		return err
+	}
+}
+
+const (
+	// fillFileName is the file allocated to fill up filesystem
+	fillFileName = ".robotest-fill"
+	// speedFileName is the file written to measure filesystem throughput
+	speedFileName = ".robotest-speed"
+)
+
+// FillDisk allocates a file on filesystem holding path so that it becomes percent full
+func (g *gravity) FillDisk(ctx context.Context, path string, percent uint) (RestoreFn, error) {
+	if percent > 100 {
+		return nil, trace.BadParameter("percent should be within 0..100, got %v", percent)
+	}
+
+	var out string
+	cmd := fmt.Sprintf("df --output=size,used -B1 %s", path)
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return nil, trace.Wrap(err, cmd)
+	}
+	if exit != 0 {
+		return nil, trace.Errorf("%s returned %d", cmd, exit)
+	}
+	size, used, err := parseDiskUsage(out)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	fillPath := filepath.Join(path, fillFileName)
+	restore := func(ctx context.Context) error {
+		g.Logger().WithField("path", fillPath).Info("RESTORE: disk space")
+		return trace.Wrap(sshutils.Run(ctx, g.Client(), g.Logger(), fmt.Sprintf("sudo rm -f %s", fillPath), nil))
+	}
+
+	target := size / 100 * uint64(percent)
+	log := g.Logger().WithFields(logrus.Fields{"path": fillPath, "size": size, "used": used, "percent": percent})
+	if used >= target {
+		return nil, trace.CompareFailed("filesystem holding %v is already %v%% full, can not fill it up to %v%%",
+			path, used*100/size, percent)
+	}
+
+	log.Warn("FAULT: fill disk")
+	err = sshutils.Run(ctx, g.Client(), g.Logger(),
+		fmt.Sprintf("sudo fallocate -l %d %s", target-used, fillPath), nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return restore, nil
+}
+
+// RemountReadOnly remounts filesystem holding path read-only
+func (g *gravity) RemountReadOnly(ctx context.Context, path string) (RestoreFn, error) {
+	var mountpoint string
+	cmd := fmt.Sprintf("findmnt -n -o TARGET --target %s", path)
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseAsString(&mountpoint))
+	if err != nil {
+		return nil, trace.Wrap(err, cmd)
+	}
+	if exit != 0 {
+		return nil, trace.Errorf("%s returned %d", cmd, exit)
+	}
+	mountpoint = strings.TrimSpace(mountpoint)
+	if mountpoint == "" {
+		return nil, trace.NotFound("no mount point for %v", path)
+	}
+
+	g.Logger().WithField("mountpoint", mountpoint).Warn("FAULT: remount read-only")
+	err = sshutils.Run(ctx, g.Client(), g.Logger(), fmt.Sprintf("sudo mount -o remount,ro %s", mountpoint), nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return func(ctx context.Context) error {
+		g.Logger().WithField("mountpoint", mountpoint).Info("RESTORE: remount read-write")
+		return trace.Wrap(sshutils.Run(ctx, g.Client(), g.Logger(),
+			fmt.Sprintf("sudo mount -o remount,rw %s", mountpoint), nil))
+	}, nil
+}
+
+// ThrottleDevice limits read and write throughput of block device via cgroup blkio controller.
+// device may also be a path on the filesystem, then disk holding it is throttled.
+// Empty device refers to docker device of the node.
+// Limits apply to cgroups existing at the moment, i.e. to Planet and its containers
+func (g *gravity) ThrottleDevice(ctx context.Context, device string, bytesPerSec uint64) (RestoreFn, error) {
+	if bytesPerSec == 0 {
+		return nil, trace.BadParameter("throughput limit should be positive")
+	}
+	if device == "" {
+		device = g.param.dockerDevice
+	}
+
+	log := g.Logger().WithFields(logrus.Fields{"device": device, "bps": bytesPerSec})
+	log.Warn("FAULT: throttle device")
+	err := sshutils.Run(ctx, g.Client(), g.Logger(), blkioThrottleCmd(device, bytesPerSec), nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return func(ctx context.Context) error {
+		log.Info("RESTORE: device throughput")
+		// zero limit removes the rule
+		return trace.Wrap(sshutils.Run(ctx, g.Client(), g.Logger(), blkioThrottleCmd(device, 0), nil))
+	}, nil
+}
+
+// WriteSpeed measures direct write throughput of filesystem holding path on the node, in bytes per second.
+// Direct I/O bypasses page cache, so that write is subject to blkio throttling of the session
+func WriteSpeed(ctx context.Context, node Gravity, path string) (uint64, error) {
+	file := filepath.Join(path, speedFileName)
+	defer sshutils.Run(ctx, node.Client(), node.Logger(), fmt.Sprintf("sudo rm -f %s", file), nil)
+
+	var out string
+	cmd := fmt.Sprintf("sudo dd if=/dev/zero of=%s bs=100K count=1024 oflag=direct 2>&1", file)
+	exit, err := sshutils.RunAndParse(ctx, node.Client(), node.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return 0, trace.Wrap(err, cmd)
+	}
+	if exit != 0 {
+		return 0, trace.Errorf("%s returned %d: %s", cmd, exit, out)
+	}
+	speed, err := ParseDDOutput(out)
+	return speed, trace.Wrap(err)
+}
+
+// WaitWriteSpeed waits until write throughput of filesystem holding path on the node
+// reaches minSpeed, and returns how long it took
+func (c *TestContext) WaitWriteSpeed(node Gravity, path string, minSpeed uint64) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       diskSpeedWait,
+		FieldLogger: node.Logger().WithField("path", path),
+	}
+
+	start := time.Now()
+	err := retry.Do(ctx, func() error {
+		speed, err := WriteSpeed(ctx, node, path)
+		if err != nil {
+			return wait.Abort(trace.Wrap(err))
+		}
+		if speed < minSpeed {
+			return wait.Continue(fmt.Sprintf("%s has %v/s < expected %v/s",
+				path, humanize.Bytes(speed), humanize.Bytes(minSpeed)))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	return time.Since(start), nil
+}
+
+// blkioThrottleCmd sets read and write bps limit for the disk in every blkio cgroup
+func blkioThrottleCmd(device string, bytesPerSec uint64) string {
+	return fmt.Sprintf(`SRC=%s; test -b $SRC || SRC=$(findmnt -n -o SOURCE --target $SRC); \
+		DISK=$(lsblk -no PKNAME $SRC | head -1); test -n "$DISK" && SRC=/dev/$DISK; \
+		DEV=$(lsblk -dno MAJ:MIN $SRC | tr -d ' ') && test -n "$DEV" && \
+		for f in $(sudo find /sys/fs/cgroup/blkio -name 'blkio.throttle.*_bps_device'); do \
+			echo "$DEV %d" | sudo tee $f >/dev/null || exit 1; \
+		done`, device, bytesPerSec)
+}
@@ -0,0 +1,94 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Host misconfigurations are applied before install to verify preflight checks reject the host
+
+// moduleBlacklistFile prevents unloaded kernel modules from being loaded back on demand
+const moduleBlacklistFile = "/etc/modprobe.d/robotest-blacklist.conf"
+
+// UnloadKernelModules unloads kernel modules on the node and prevents them from being loaded again
+func UnloadKernelModules(ctx context.Context, node Gravity, modules ...string) (RestoreFn, error) {
+	if len(modules) == 0 {
+		return nil, trace.BadParameter("no kernel modules to unload")
+	}
+
+	blacklist := []string{}
+	for _, module := range modules {
+		blacklist = append(blacklist, fmt.Sprintf("install %s /bin/false", module))
+	}
+
+	node.Logger().WithField("modules", modules).Warn("FAULT: unload kernel modules")
+	err := sshutils.RunCommands(ctx, node.Client(), node.Logger(), []sshutils.Cmd{
+		{Command: fmt.Sprintf("printf '%s\\n' | sudo tee %s", strings.Join(blacklist, `\n`), moduleBlacklistFile)},
+		{Command: fmt.Sprintf("sudo modprobe -r %s", strings.Join(modules, " "))},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return func(ctx context.Context) error {
+		node.Logger().WithField("modules", modules).Info("RESTORE: load kernel modules")
+		return trace.Wrap(sshutils.RunCommands(ctx, node.Client(), node.Logger(), []sshutils.Cmd{
+			{Command: fmt.Sprintf("sudo rm -f %s", moduleBlacklistFile)},
+			{Command: fmt.Sprintf("sudo modprobe -a %s", strings.Join(modules, " "))},
+		}))
+	}, nil
+}
+
+// portListener listens on a port forever, compatible with python 2 and 3
+const portListener = `import socket,time
+s=socket.socket()
+s.setsockopt(socket.SOL_SOCKET,socket.SO_REUSEADDR,1)
+s.bind(("0.0.0.0",%d))
+s.listen(1)
+time.sleep(1e9)`
+
+// OccupyPorts starts listeners on TCP ports on the node
+func OccupyPorts(ctx context.Context, node Gravity, ports ...int) (RestoreFn, error) {
+	units := []string{}
+	for _, port := range ports {
+		unit := fmt.Sprintf("robotest-port-%d", port)
+		cmd := fmt.Sprintf(`sudo systemd-run --unit=%s $(command -v python3 || command -v python) -c '%s'`,
+			unit, fmt.Sprintf(portListener, port))
+		node.Logger().WithField("port", port).Warn("FAULT: occupy port")
+		err := sshutils.Run(ctx, node.Client(), node.Logger(), cmd, nil)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		units = append(units, unit)
+	}
+
+	return func(ctx context.Context) error {
+		node.Logger().WithField("ports", ports).Info("RESTORE: release ports")
+		return trace.Wrap(sshutils.Run(ctx, node.Client(), node.Logger(),
+			fmt.Sprintf("sudo systemctl stop %s", strings.Join(units, " ")), nil))
+	}, nil
+}
+
+// SetDockerDevice overrides docker device passed to install or join on the node
+func SetDockerDevice(node Gravity, device string) (RestoreFn, error) {
+	g, ok := node.(*gravity)
+	if !ok {
+		return nil, trace.BadParameter("unexpected node type %T", node)
+	}
+
+	original := g.param.dockerDevice
+	g.Logger().WithFields(logrus.Fields{"device": device, "original": original}).Warn("FAULT: docker device")
+	g.param.dockerDevice = device
+
+	return func(ctx context.Context) error {
+		g.Logger().WithField("device", original).Info("RESTORE: docker device")
+		g.param.dockerDevice = original
+		return nil
+	}, nil
+}
@@ -0,0 +1,210 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// faultChain is iptables chain holding rules injected by robotest
+const faultChain = "ROBOTEST-FAULT"
+
+// networkFaults keeps track of nodes affected by network fault injection
+type networkFaults struct {
+	sync.Mutex
+	partitioned map[Gravity]bool
+	degraded    map[Gravity]bool
+}
+
+// Partition blocks all traffic between nodes of groupA and groupB, until Heal is called.
+// Traffic to and from the test host is not affected
+func (c *TestContext) Partition(groupA, groupB []Gravity) error {
+	if len(groupA) == 0 || len(groupB) == 0 {
+		return trace.BadParameter("both groups should have nodes")
+	}
+	for _, a := range groupA {
+		for _, b := range groupB {
+			if a == b {
+				return trace.BadParameter("node %v belongs to both groups", a)
+			}
+		}
+	}
+
+	c.Logger().WithFields(logrus.Fields{"group_a": groupA, "group_b": groupB}).Warn("FAULT: network partition")
+	c.trackNetFaults(groupA, groupB, nil)
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	nodes := append(append([]Gravity{}, groupA...), groupB...)
+	errs := make(chan error, len(nodes))
+	for _, node := range groupA {
+		go func(n Gravity) {
+			errs <- partitionNode(ctx, n, groupB)
+		}(node)
+	}
+	for _, node := range groupB {
+		go func(n Gravity) {
+			errs <- partitionNode(ctx, n, groupA)
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
+
+// AddLatency degrades network on nodes by adding delay with jitter and packet loss (percent),
+// until Heal is called
+func (c *TestContext) AddLatency(nodes []Gravity, delay, jitter time.Duration, loss float64) error {
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+	if delay < 0 || jitter < 0 || loss < 0 || loss > 100 {
+		return trace.BadParameter("delay=%v, jitter=%v, loss=%v%%", delay, jitter, loss)
+	}
+
+	c.Logger().WithFields(logrus.Fields{
+		"nodes": nodes, "delay": delay.String(), "jitter": jitter.String(), "loss": loss,
+	}).Warn("FAULT: network latency")
+	c.trackNetFaults(nil, nil, nodes)
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(n Gravity) {
+			errs <- degradeNode(ctx, n, delay, jitter, loss)
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
+
+// Heal removes all network faults previously injected with Partition and AddLatency
+func (c *TestContext) Heal() error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	return trace.Wrap(c.healNetwork(ctx))
+}
+
+// trackNetFaults records affected nodes and makes sure faults are healed on teardown
+func (c *TestContext) trackNetFaults(groupA, groupB, degraded []Gravity) {
+	faults := &c.netFaults
+	faults.Lock()
+	defer faults.Unlock()
+
+	if faults.partitioned == nil && faults.degraded == nil {
+		c.OnTeardown("heal network", c.healNetwork)
+	}
+	if faults.partitioned == nil {
+		faults.partitioned = map[Gravity]bool{}
+	}
+	if faults.degraded == nil {
+		faults.degraded = map[Gravity]bool{}
+	}
+
+	for _, node := range groupA {
+		faults.partitioned[node] = true
+	}
+	for _, node := range groupB {
+		faults.partitioned[node] = true
+	}
+	for _, node := range degraded {
+		faults.degraded[node] = true
+	}
+}
+
+func (c *TestContext) healNetwork(ctx context.Context) error {
+	faults := &c.netFaults
+	faults.Lock()
+	defer faults.Unlock()
+
+	var errors []error
+	for node := range faults.partitioned {
+		if node.Offline() {
+			continue
+		}
+		if err := healPartition(ctx, node); err != nil {
+			errors = append(errors, trace.Wrap(err, "healing partition on %v", node))
+			continue
+		}
+		delete(faults.partitioned, node)
+	}
+	for node := range faults.degraded {
+		if node.Offline() {
+			continue
+		}
+		if err := healDegradation(ctx, node); err != nil {
+			errors = append(errors, trace.Wrap(err, "removing latency on %v", node))
+			continue
+		}
+		delete(faults.degraded, node)
+	}
+
+	if len(errors) != 0 {
+		err := trace.NewAggregate(errors...)
+		c.Logger().WithError(err).Error("failed to heal network")
+		return err
+	}
+	c.Logger().Info("network healed")
+	return nil
+}
+
+// partitionNode drops any traffic between node and peers using dedicated iptables chain
+func partitionNode(ctx context.Context, node Gravity, peers []Gravity) error {
+	cmds := []sshutils.Cmd{
+		{Command: fmt.Sprintf("sudo iptables -N %s || true", faultChain)},
+		{Command: fmt.Sprintf("sudo iptables -C INPUT -j %[1]s || sudo iptables -I INPUT -j %[1]s", faultChain)},
+		{Command: fmt.Sprintf("sudo iptables -C OUTPUT -j %[1]s || sudo iptables -I OUTPUT -j %[1]s", faultChain)},
+	}
+	for _, peer := range peers {
+		addr := peer.Node().PrivateAddr()
+		cmds = append(cmds,
+			sshutils.Cmd{Command: fmt.Sprintf("sudo iptables -A %s -s %s -j DROP", faultChain, addr)},
+			sshutils.Cmd{Command: fmt.Sprintf("sudo iptables -A %s -d %s -j DROP", faultChain, addr)})
+	}
+
+	err := sshutils.RunCommands(ctx, node.Client(), node.Logger(), cmds)
+	return trace.Wrap(err)
+}
+
+// healPartition removes robotest iptables chain along with rules jumping to it
+func healPartition(ctx context.Context, node Gravity) error {
+	cmds := []sshutils.Cmd{
+		{Command: fmt.Sprintf("sudo iptables -D INPUT -j %s 2>/dev/null || true", faultChain)},
+		{Command: fmt.Sprintf("sudo iptables -D OUTPUT -j %s 2>/dev/null || true", faultChain)},
+		{Command: fmt.Sprintf("sudo iptables -F %s 2>/dev/null || true", faultChain)},
+		{Command: fmt.Sprintf("sudo iptables -X %s 2>/dev/null || true", faultChain)},
+	}
+	err := sshutils.RunCommands(ctx, node.Client(), node.Logger(), cmds)
+	return trace.Wrap(err)
+}
+
+// degradeNode applies tc netem discipline to the interface of node private address
+func degradeNode(ctx context.Context, node Gravity, delay, jitter time.Duration, loss float64) error {
+	cmd := fmt.Sprintf("%s && sudo tc qdisc replace dev $IFACE root netem delay %dms %dms loss %.2f%%",
+		privateIfaceCmd(node), delay/time.Millisecond, jitter/time.Millisecond, loss)
+	err := sshutils.Run(ctx, node.Client(), node.Logger(), cmd, nil)
+	return trace.Wrap(err)
+}
+
+// healDegradation removes tc netem discipline from the interface of node private address
+func healDegradation(ctx context.Context, node Gravity) error {
+	cmd := fmt.Sprintf("%s && (sudo tc qdisc del dev $IFACE root 2>/dev/null || true)", privateIfaceCmd(node))
+	err := sshutils.Run(ctx, node.Client(), node.Logger(), cmd, nil)
+	return trace.Wrap(err)
+}
+
+// privateIfaceCmd resolves network interface of node private address into $IFACE
+func privateIfaceCmd(node Gravity) string {
+	return fmt.Sprintf(`IFACE=$(ip -o -4 addr show | awk '$4 ~ /^%s\// {print $2}') && test -n "$IFACE"`,
+		node.Node().PrivateAddr())
+}
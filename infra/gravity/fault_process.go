@@ -0,0 +1,147 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/utils"
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Component is a system service which could be killed to inject a fault
+type Component string
+
+const (
+	// ComponentEtcd is etcd service inside Planet
+	ComponentEtcd Component = "etcd"
+	// ComponentAPIServer is Kubernetes API server inside Planet
+	ComponentAPIServer Component = "kube-apiserver"
+	// ComponentKubelet is kubelet inside Planet
+	ComponentKubelet Component = "kubelet"
+	// ComponentDocker is docker daemon inside Planet
+	ComponentDocker Component = "docker"
+	// ComponentPlanet is Planet container itself
+	ComponentPlanet Component = "planet"
+)
+
+// unit returns systemd unit (or unit pattern) of the component,
+// and whether it is managed by systemd inside Planet or on the host
+func (c Component) unit() (name string, inPlanet bool, err error) {
+	switch c {
+	case ComponentEtcd:
+		return "etcd.service", true, nil
+	case ComponentAPIServer:
+		return "kube-apiserver.service", true, nil
+	case ComponentKubelet:
+		return "kube-kubelet.service", true, nil
+	case ComponentDocker:
+		return "docker.service", true, nil
+	case ComponentPlanet:
+		return "'gravity__gravitational.io__planet*'", false, nil
+	default:
+		return "", false, trace.BadParameter("unknown component %q", c)
+	}
+}
+
+// KillComponent sends SIGKILL to all processes of the component on the node
+func KillComponent(ctx context.Context, node Gravity, component Component) error {
+	unit, inPlanet, err := component.unit()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	node.Logger().WithField("component", component).Warn("FAULT: kill")
+	if inPlanet {
+		_, err = node.RunInPlanet(ctx, "/bin/systemctl", "kill", "--signal=SIGKILL", unit)
+		return trace.Wrap(err)
+	}
+
+	err = sshutils.Run(ctx, node.Client(), node.Logger(),
+		fmt.Sprintf("sudo systemctl kill --signal=SIGKILL %s", unit), nil)
+	return trace.Wrap(err)
+}
+
+// WaitComponent waits until component is active on the node
+func WaitComponent(ctx context.Context, node Gravity, component Component) error {
+	unit, inPlanet, err := component.unit()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       componentRecoveryWait,
+		FieldLogger: node.Logger().WithField("component", component),
+	}
+
+	return trace.Wrap(retry.Do(ctx, func() error {
+		var out string
+		if inPlanet {
+			out, err = node.RunInPlanet(ctx, "/bin/systemctl", "is-active", unit)
+		} else {
+			_, err = sshutils.RunAndParse(ctx, node.Client(), node.Logger(),
+				fmt.Sprintf("systemctl is-active %s", unit), nil, sshutils.ParseAsString(&out))
+		}
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		if strings.TrimSpace(out) != "active" {
+			return wait.Continue(fmt.Sprintf("%v is %q", component, strings.TrimSpace(out)))
+		}
+		return nil
+	}))
+}
+
+// KillComponent kills component on every node
+func (c *TestContext) KillComponent(nodes []Gravity, component Component) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Fault)
+	defer cancel()
+
+	c.Logger().WithFields(logrus.Fields{"nodes": nodes, "component": component}).Warn("FAULT: kill component")
+
+	errs := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(n Gravity) {
+			errs <- KillComponent(ctx, n, component)
+		}(node)
+	}
+
+	return trace.Wrap(utils.CollectErrors(ctx, errs))
+}
+
+// WaitRecovery waits until component becomes active again on nodes it was killed on,
+// and cluster status is available on all cluster nodes.
+// It returns how long did recovery take
+func (c *TestContext) WaitRecovery(cluster, killed []Gravity, component Component) (time.Duration, error) {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	errs := make(chan error, len(killed))
+	for _, node := range killed {
+		go func(n Gravity) {
+			errs <- WaitComponent(ctx, n, component)
+		}(node)
+	}
+
+	if err := utils.CollectErrors(ctx, errs); err != nil {
+		return time.Since(start), trace.Wrap(err)
+	}
+
+	if err := c.Status(cluster); err != nil {
+		return time.Since(start), trace.Wrap(err)
+	}
+
+	elapsed := time.Since(start)
+	c.Logger().WithFields(logrus.Fields{
+		"nodes": killed, "component": component, "elapsed": elapsed.String(),
+	}).Info("recovered")
+	return elapsed, nil
+}
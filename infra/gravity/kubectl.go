@@ -13,6 +13,14 @@ type Pod struct {
 	Name   string
 	Ready  bool
 	NodeIP string
+	Phase  string
+}
+
+// KubeNode is Kubernetes node as reported by API server
+type KubeNode struct {
+	// IP is node internal IP address
+	IP    string
+	Ready bool
 }
 
 const (
@@ -23,7 +31,7 @@ const (
 func KubectlGetPods(ctx context.Context, g Gravity, namespace, label string) ([]Pod, error) {
 	args := []string{
 		"get", "pods", "-n", namespace,
-		`-ojsonpath='{range .items[*]}{.metadata.name},{.status.conditions[?(@.type=="Ready")].status},{.status.hostIP}{"\n"}{end}'`,
+		`-ojsonpath='{range .items[*]}{.metadata.name},{.status.conditions[?(@.type=="Ready")].status},{.status.hostIP},{.status.phase}{"\n"}{end}'`,
 	}
 	if label != "" {
 		args = append(args, "-l", label)
@@ -41,16 +49,44 @@ func KubectlGetPods(ctx context.Context, g Gravity, namespace, label string) ([]
 		if line == "" {
 			continue
 		}
-		if len(v) != 3 {
+		if len(v) != 4 {
 			return nil, trace.Errorf("unexpected string %q", line)
 		}
 
-		pods = append(pods, Pod{Name: v[0], Ready: v[1] == "True", NodeIP: v[2]})
+		pods = append(pods, Pod{Name: v[0], Ready: v[1] == "True", NodeIP: v[2], Phase: v[3]})
 	}
 
 	return pods, nil
 }
 
+// KubectlGetNodes returns Kubernetes nodes and their readiness
+func KubectlGetNodes(ctx context.Context, g Gravity) ([]KubeNode, error) {
+	out, err := g.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "nodes",
+		`-ojsonpath='{range .items[*]}{.status.addresses[?(@.type=="InternalIP")].address},{.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}'`)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return parseKubeNodes(out)
+}
+
+func parseKubeNodes(out string) ([]KubeNode, error) {
+	nodes := []KubeNode{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		v := strings.Split(line, ",")
+		if len(v) != 2 {
+			return nil, trace.Errorf("unexpected string %q", line)
+		}
+
+		nodes = append(nodes, KubeNode{IP: v[0], Ready: v[1] == "True"})
+	}
+	return nodes, nil
+}
+
 func KubectlDeletePod(ctx context.Context, g Gravity, namespace, pod string) error {
 	out, err := g.RunInPlanet(ctx, "/usr/bin/kubectl", "delete", "po", "-n", namespace, pod)
 	if err != nil {
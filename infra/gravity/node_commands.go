@@ -1,14 +1,12 @@
 package gravity
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/gravitational/robotest/infra"
@@ -18,6 +16,7 @@ import (
 	"github.com/gravitational/robotest/lib/wait"
 	"github.com/gravitational/trace"
 
+	semver "github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 )
@@ -33,8 +32,9 @@ type Gravity interface {
 	Install(ctx context.Context, param InstallParam) error
 	// Status retrieves status
 	Status(ctx context.Context) (*GravityStatus, error)
-	// OfflineUpdate tries to upgrade application version
-	OfflineUpdate(ctx context.Context, installerUrl string) error
+	// OfflineUpdate uploads packages of current installer (see SetInstaller) to the local cluster
+	// and updates application to its version, waiting for update operation to complete
+	OfflineUpdate(ctx context.Context) error
 	// Join asks to join existing cluster (or installation in progress)
 	Join(ctx context.Context, param JoinCmd) error
 	// Leave requests current node leave a cluster
@@ -45,16 +45,47 @@ type Gravity interface {
 	Uninstall(ctx context.Context) error
 	// PowerOff will power off the node
 	PowerOff(ctx context.Context, graceful Graceful) error
+	// PowerOn powers on the node via cloud provider API and reconnects to it
+	PowerOn(ctx context.Context) error
+	// HardReset resets the node via cloud provider API and reconnects to it
+	HardReset(ctx context.Context) error
 	// Reboot will reboot this node and wait until it will become available again
 	Reboot(ctx context.Context, graceful Graceful) error
 	// CollectLogs will pull essential logs from node and store it in state dir under node-logs/prefix
 	CollectLogs(ctx context.Context, prefix string) (localPath string, err error)
+	// Backup creates backup of cluster application in file on the node,
+	// and copies it into state dir
+	Backup(ctx context.Context, file string) (localPath string, err error)
+	// Restore restores cluster application from backup file on the node
+	Restore(ctx context.Context, file string) error
 	// Upload uploads packages in current installer dir to cluster
 	Upload(ctx context.Context) error
 	// Upgrade takes currently active installer (see SetInstaller) and tries to perform upgrade
 	Upgrade(ctx context.Context) error
+	// UpgradeManual launches upgrade operation with currently active installer in manual mode,
+	// its phases are then executed one by one with ExecutePhase
+	UpgradeManual(ctx context.Context) error
+	// Plan returns phases of the operation in progress
+	Plan(ctx context.Context) ([]PlanPhase, error)
+	// ExecutePhase executes phase of the operation in progress
+	ExecutePhase(ctx context.Context, phase string) error
+	// InterruptPhase starts executing phase of the operation in progress and kills it after given time,
+	// simulating failure in the middle of the phase
+	InterruptPhase(ctx context.Context, phase string, after time.Duration) error
+	// RollbackPhase rolls back phase of the operation in progress
+	RollbackPhase(ctx context.Context, phase string) error
+	// CompletePlan marks operation in progress as completed, or failed if it was not
+	CompletePlan(ctx context.Context) error
 	// RunInPlanet runs specific command inside Planet container and returns its result
 	RunInPlanet(ctx context.Context, cmd string, args ...string) (string, error)
+	// FillDisk allocates space on filesystem holding path until it is percent full
+	FillDisk(ctx context.Context, path string, percent uint) (RestoreFn, error)
+	// RemountReadOnly remounts filesystem holding path read-only
+	RemountReadOnly(ctx context.Context, path string) (RestoreFn, error)
+	// ThrottleDevice limits throughput of block device, docker device if empty
+	ThrottleDevice(ctx context.Context, device string, bytesPerSec uint64) (RestoreFn, error)
+	// Version returns gravity version of current installer (see SetInstaller), nil if unknown
+	Version() *semver.Version
 	// Node returns underlying VM instance
 	Node() infra.Node
 	// Offline returns true if node was previously powered off
@@ -97,6 +128,14 @@ type InstallParam struct {
 	OSFlavor OS `json:"os" validate:"required"`
 	// DockerStorageDriver is one of supported storage drivers
 	DockerStorageDriver StorageDriver `json:"storage_driver"`
+	// ExtraArgs (Optional) are extra arguments appended to gravity install command
+	ExtraArgs []string `json:"extra_args,omitempty"`
+	// Env (Optional) are environment variables gravity install is run with
+	Env map[string]string `json:"env,omitempty"`
+	// ExpandRoles (Optional) are roles of nodes joining on expand, in order of nodes. Role is used for the rest
+	ExpandRoles []string `json:"expand_roles,omitempty"`
+	// ExpandParallel (Optional) whether nodes join concurrently on expand, rather than one by one
+	ExpandParallel bool `json:"expand_parallel"`
 }
 
 // JoinCmd represents various parameters for Join
@@ -113,15 +152,53 @@ type JoinCmd struct {
 	StateDir string
 }
 
+const (
+	// StatusActive is reported by healthy cluster
+	StatusActive = "active"
+	// StatusDegraded is reported when some of cluster nodes are unhealthy
+	StatusDegraded = "degraded"
+)
+
 // GravityStatus is serialized form of `gravity status` CLI.
 type GravityStatus struct {
+	// Application is application name
 	Application string
-	Cluster     string
-	Status      string
+	// AppVersion is application version
+	AppVersion string
+	Cluster    string
+	Status     string
+	// Reason explains why cluster is not active, i.e. degraded
+	Reason string
 	// Token is secure token which prevents rogue nodes from joining the cluster during installation
 	Token string `validation:"required"`
 	// Nodes defines nodes the cluster observes
 	Nodes []string
+	// NodeStatus is detailed status of cluster nodes, only available with JSON output
+	NodeStatus []NodeStatus
+	// Operations are operations currently in progress, only available with JSON output
+	Operations []ClusterOperation
+}
+
+// NodeStatus is status of a node as observed by the cluster
+type NodeStatus struct {
+	Hostname string
+	// Addr is advertise address of the node
+	Addr string
+	// Role is node role, i.e. master or node
+	Role string
+	// Profile is node profile as defined in app.yaml
+	Profile string
+	// Status is node health, i.e. healthy or degraded
+	Status string
+	// FailedProbes are health checks failed on the node
+	FailedProbes []string
+}
+
+// ClusterOperation is an operation running in the cluster
+type ClusterOperation struct {
+	ID    string
+	Type  string
+	State string
 }
 
 type gravity struct {
@@ -131,6 +208,12 @@ type gravity struct {
 	param      cloudDynamicParams
 	ts         time.Time
 	log        logrus.FieldLogger
+	// airGapped is whether egress from the node is blocked, see AirGap
+	airGapped bool
+	// version is gravity version of current installer, nil if unknown
+	version *semver.Version
+	// env is environment of gravity install and join, i.e. proxy settings, see UseProxy
+	env map[string]string
 }
 
 func (g *gravity) MarshalJSON() ([]byte, error) {
@@ -181,53 +264,80 @@ func (g *gravity) Client() *ssh.Client {
 	return g.ssh
 }
 
-// Install runs gravity install with params
+// Install runs gravity install with params,
+// license and Kubernetes resources files are transferred to the node first
 func (g *gravity) Install(ctx context.Context, param InstallParam) error {
-	// cmd specify additional configuration for the install command
-	// collected from defaults and/or computed values
-	type cmd struct {
-		InstallDir      string
-		PrivateAddr     string
-		EnvDockerDevice string
-		StorageDriver   string
-		InstallParam
-	}
-
-	var buf bytes.Buffer
-	err := installCmdTemplate.Execute(&buf, cmd{
+	var licenseFile, k8sConfigFile string
+	var err error
+	if param.LicenseURL != "" {
+		licenseFile, err = g.transferFile(ctx, param.LicenseURL)
+		if err != nil {
+			return trace.Wrap(err, "transferring license %v", param.LicenseURL)
+		}
+	}
+	if param.K8SConfigURL != "" {
+		k8sConfigFile, err = g.transferFile(ctx, param.K8SConfigURL)
+		if err != nil {
+			return trace.Wrap(err, "transferring Kubernetes resources %v", param.K8SConfigURL)
+		}
+	}
+
+	env, err := sudoEnv(g.env, param.Env)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	cmd, err := renderCmd(installCmdTemplate, installCmd{
 		InstallDir:      g.installDir,
 		PrivateAddr:     g.Node().PrivateAddr(),
 		EnvDockerDevice: constants.EnvDockerDevice,
 		StorageDriver:   g.param.storageDriver.Driver(),
+		LicenseFile:     licenseFile,
+		K8SConfigFile:   k8sConfigFile,
+		SystemLogFile:   systemLogFile,
+		SudoEnv:         env,
+		Flags:           flagsForVersion(g.version),
 		InstallParam:    param,
 	})
 	if err != nil {
-		return trace.Wrap(err, buf.String())
+		return trace.Wrap(err)
 	}
 
-	err = sshutils.Run(ctx, g.Client(), g.Logger(), buf.String(), map[string]string{
+	err = sshutils.Run(ctx, g.Client(), g.Logger(), cmd, map[string]string{
 		constants.EnvDockerDevice: g.param.dockerDevice,
 	})
 	return trace.Wrap(err, param)
 }
 
-var installCmdTemplate = template.Must(
-	template.New("gravity_install").Parse(`
-		source /tmp/gravity_environment >/dev/null 2>&1 || true; \
-		cd {{.InstallDir}} && ./gravity version && sudo ./gravity install --debug \
-		--advertise-addr={{.PrivateAddr}} --token={{.Token}} --flavor={{.Flavor}} \
-		--docker-device=${{.EnvDockerDevice}} \
-		{{if .StorageDriver}}--storage-driver={{.StorageDriver}}{{end}} \
-		--system-log-file=./telekube-system.log \
-		--cloud-provider={{.CloudProvider}} --state-dir={{.StateDir}} \
-		{{if .Cluster}}--cluster={{.Cluster}}{{end}}
-`))
+// transferFile transfers file from local path, S3 or HTTP(s) URL into the install dir
+func (g *gravity) transferFile(ctx context.Context, fileURL string) (path string, err error) {
+	err = g.withEgress(ctx, fileURL, func() (err error) {
+		path, err = sshutils.TransferFile(ctx, g.Client(), g.Logger(), fileURL, g.installDir, g.param.env)
+		return trace.Wrap(err)
+	})
+	return path, trace.Wrap(err)
+}
 
-// Status queries cluster status
+// Status queries cluster status, using JSON output if supported by gravity version
 func (g *gravity) Status(ctx context.Context) (*GravityStatus, error) {
-	cmd := "sudo gravity status --system-log-file=./telekube-system.log"
+	var out string
+	flags := flagsForVersion(g.version)
+	cmd := statusCmd(flags, true)
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return nil, trace.Wrap(err, cmd)
+	}
+	if exit == 0 {
+		status, err := parseStatusJSON([]byte(out))
+		if err == nil {
+			return status, nil
+		}
+		g.Logger().WithError(err).Debug("unexpected JSON status, will fall back to text")
+	}
+
+	cmd = statusCmd(flags, false)
 	status := GravityStatus{}
-	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, parseStatus(&status))
+	exit, err = sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, parseStatus(&status))
 
 	if err != nil {
 		return nil, trace.Wrap(err, cmd)
@@ -241,43 +351,44 @@ func (g *gravity) Status(ctx context.Context) (*GravityStatus, error) {
 	return &status, nil
 }
 
-func (g *gravity) OfflineUpdate(ctx context.Context, installerUrl string) error {
-	return nil
+// OfflineUpdate updates cluster application with `gravity update` from current installer,
+// without Ops Center
+func (g *gravity) OfflineUpdate(ctx context.Context) error {
+	flags := flagsForVersion(g.version)
+	cmd := updateUploadCmd(g.installDir, flags)
+	err := sshutils.Run(ctx, g.Client(), g.Logger(), cmd, nil)
+	if err != nil {
+		return trace.Wrap(err, cmd)
+	}
+
+	return trace.Wrap(g.runOp(ctx, updateArgs(flags)))
 }
 
 func (g *gravity) Join(ctx context.Context, param JoinCmd) error {
-	// cmd specify additional configuration for the join command
-	// collected from defaults and/or computed values
-	type cmd struct {
-		InstallDir, PrivateAddr, EnvDockerDevice string
-		JoinCmd
+	env, err := sudoEnv(g.env)
+	if err != nil {
+		return trace.Wrap(err)
 	}
 
-	var buf bytes.Buffer
-	err := joinCmdTemplate.Execute(&buf, cmd{
+	cmd, err := renderCmd(joinCmdTemplate, joinCmd{
 		InstallDir:      g.installDir,
 		PrivateAddr:     g.Node().PrivateAddr(),
 		EnvDockerDevice: constants.EnvDockerDevice,
+		SystemLogFile:   systemLogFile,
+		SudoEnv:         env,
+		Flags:           flagsForVersion(g.version),
 		JoinCmd:         param,
 	})
 	if err != nil {
-		return trace.Wrap(err, buf.String())
+		return trace.Wrap(err)
 	}
 
-	err = sshutils.Run(ctx, g.Client(), g.Logger(), buf.String(), map[string]string{
+	err = sshutils.Run(ctx, g.Client(), g.Logger(), cmd, map[string]string{
 		constants.EnvDockerDevice: g.param.dockerDevice,
 	})
 	return trace.Wrap(err, param)
 }
 
-var joinCmdTemplate = template.Must(
-	template.New("gravity_join").Parse(`
-		source /tmp/gravity_environment >/dev/null 2>&1 || true; \
-		cd {{.InstallDir}} && sudo ./gravity join {{.PeerAddr}} \
-		--advertise-addr={{.PrivateAddr}} --token={{.Token}} --debug \
-		--role={{.Role}} --docker-device=${{.EnvDockerDevice}} \
-		--system-log-file=./telekube-system.log --state-dir={{.StateDir}}`))
-
 // Leave makes given node leave the cluster
 func (g *gravity) Leave(ctx context.Context, graceful Graceful) error {
 	var cmd string
@@ -303,7 +414,7 @@ func (g *gravity) Remove(ctx context.Context, node string, graceful Graceful) er
 
 // Uninstall removes gravity installation. It requires Leave beforehand
 func (g *gravity) Uninstall(ctx context.Context) error {
-	cmd := fmt.Sprintf(`cd %s && sudo ./gravity system uninstall --confirm --system-log-file=./telekube-system.log`, g.installDir)
+	cmd := uninstallCmd(g.installDir, flagsForVersion(g.version))
 	err := sshutils.Run(ctx, g.Client(), g.Logger(), cmd, nil)
 	return trace.Wrap(err, cmd)
 }
@@ -318,8 +429,8 @@ func (g *gravity) PowerOff(ctx context.Context, graceful Graceful) error {
 	}
 
 	sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, nil)
-	g.ssh = nil
-	// TODO: reliably destinguish between force close of SSH control channel and command being unable to run
+	g.disconnect()
+	// TODO: reliably distinguish between force close of SSH control channel and command being unable to run
 	return nil
 }
 
@@ -329,6 +440,11 @@ func (g *gravity) Offline() bool {
 
 // Reboot gracefully restarts a machine and waits for it to become available again
 func (g *gravity) Reboot(ctx context.Context, graceful Graceful) error {
+	bootID, err := g.bootID(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	var cmd string
 	if graceful {
 		cmd = "sudo shutdown -r now"
@@ -336,8 +452,70 @@ func (g *gravity) Reboot(ctx context.Context, graceful Graceful) error {
 		cmd = "sudo reboot -f"
 	}
 	sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, nil)
-	// TODO: reliably destinguish between force close of SSH control channel and command being unable to run
+	// TODO: reliably distinguish between force close of SSH control channel and command being unable to run
+
+	// node may still accept connections while shutting down
+	err = wait.Retry(ctx, func() error {
+		if err := g.reconnect(ctx); err != nil {
+			return wait.Abort(trace.Wrap(err))
+		}
+		newID, err := g.bootID(ctx)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		if newID == bootID {
+			return wait.Continue("node has not restarted yet")
+		}
+		return nil
+	})
+	return trace.Wrap(err)
+}
+
+// bootID returns unique identifier of current boot
+func (g *gravity) bootID(ctx context.Context) (string, error) {
+	var out string
+	cmd := "cat /proc/sys/kernel/random/boot_id"
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return "", trace.Wrap(err, cmd)
+	}
+	if exit != 0 {
+		return "", trace.Errorf("%s returned %d", cmd, exit)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// PowerOn powers on a machine previously powered off and waits for it to become available again
+func (g *gravity) PowerOn(ctx context.Context) error {
+	node, ok := g.node.(infra.PowerNode)
+	if !ok {
+		return trace.NotImplemented("node %v does not support power control", g)
+	}
 
+	if err := node.PowerOn(ctx); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(g.reconnect(ctx))
+}
+
+// HardReset forcibly resets a machine and waits for it to become available again
+func (g *gravity) HardReset(ctx context.Context) error {
+	node, ok := g.node.(infra.PowerNode)
+	if !ok {
+		return trace.NotImplemented("node %v does not support power control", g)
+	}
+
+	g.disconnect()
+	if err := node.HardReset(ctx); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(g.reconnect(ctx))
+}
+
+// reconnect establishes new SSH connection, as public address may change after power cycle
+func (g *gravity) reconnect(ctx context.Context) error {
+	g.disconnect()
+	g.log = g.log.WithField("public_ip", g.node.Addr())
 	client, err := sshClient(ctx, g.Node(), g.Logger())
 	if err != nil {
 		return trace.Wrap(err, "SSH reconnect")
@@ -347,6 +525,15 @@ func (g *gravity) Reboot(ctx context.Context, graceful Graceful) error {
 	return nil
 }
 
+// disconnect closes SSH connection to the node, if any
+func (g *gravity) disconnect() {
+	if g.ssh == nil {
+		return
+	}
+	g.ssh.Close()
+	g.ssh = nil
+}
+
 // PullLogs fetches essential logs from the host and stores them in state dir
 func (g *gravity) CollectLogs(ctx context.Context, prefix string) (string, error) {
 	if g.ssh == nil {
@@ -358,6 +545,26 @@ func (g *gravity) CollectLogs(ctx context.Context, prefix string) (string, error
 		fmt.Sprintf("cd %s && sudo ./gravity system report", g.installDir), localPath))
 }
 
+// Backup creates cluster application backup on the node and copies it into state dir
+func (g *gravity) Backup(ctx context.Context, file string) (string, error) {
+	cmd := backupCmd(g.installDir, file, flagsForVersion(g.version))
+	err := sshutils.Run(ctx, g.Client(), g.Logger(), cmd, nil)
+	if err != nil {
+		return "", trace.Wrap(err, cmd)
+	}
+
+	localPath := filepath.Join(g.param.StateDir, "backups", fmt.Sprintf("%s-%s", g.Node().PrivateAddr(), filepath.Base(file)))
+	return localPath, trace.Wrap(sshutils.PipeCommand(ctx, g.Client(), g.Logger(),
+		fmt.Sprintf("sudo cat %s", file), localPath))
+}
+
+// Restore restores cluster application from backup on the node
+func (g *gravity) Restore(ctx context.Context, file string) error {
+	cmd := restoreCmd(g.installDir, file, flagsForVersion(g.version))
+	err := sshutils.Run(ctx, g.Client(), g.Logger(), cmd, nil)
+	return trace.Wrap(err, cmd)
+}
+
 // SetInstaller overrides default installer into
 func (g *gravity) SetInstaller(ctx context.Context, installerURL string, subdir string) error {
 	installDir := filepath.Join(g.param.homeDir, subdir)
@@ -365,7 +572,11 @@ func (g *gravity) SetInstaller(ctx context.Context, installerURL string, subdir
 
 	log.Debugf("Set installer %v -> %v", installerURL, installDir)
 
-	tgz, err := sshutils.TransferFile(ctx, g.Client(), log, installerURL, installDir, g.param.env)
+	var tgz string
+	err := g.withEgress(ctx, installerURL, func() (err error) {
+		tgz, err = sshutils.TransferFile(ctx, g.Client(), log, installerURL, installDir, g.param.env)
+		return trace.Wrap(err)
+	})
 	if err != nil {
 		log.WithError(err).Error("Failed to transfer installer")
 		return trace.Wrap(err)
@@ -377,9 +588,28 @@ func (g *gravity) SetInstaller(ctx context.Context, installerURL string, subdir
 	}
 
 	g.installDir = installDir
+	g.version = nil
+
+	var out string
+	_, err = sshutils.RunAndParse(ctx, g.Client(), log, fmt.Sprintf("cd %s && ./gravity version", installDir),
+		nil, sshutils.ParseAsString(&out))
+	if err == nil {
+		g.version, err = parseVersion(out)
+	}
+	if err != nil {
+		log.WithError(err).Warn("Failed to detect gravity version, will assume latest")
+		return nil
+	}
+
+	log.WithField("version", g.version.String()).Info("Detected gravity version")
 	return nil
 }
 
+// Version returns gravity version of current installer, nil if unknown
+func (g *gravity) Version() *semver.Version {
+	return g.version
+}
+
 // ExecScript will transfer and execute script provided with given args
 func (g *gravity) ExecScript(ctx context.Context, scriptUrl string, args []string) error {
 	log := g.Logger().WithFields(logrus.Fields{
@@ -407,8 +637,73 @@ func (g *gravity) Upload(ctx context.Context) error {
 
 // Upgrade takes current installer and tries to perform upgrade
 func (g *gravity) Upgrade(ctx context.Context) error {
-	return trace.Wrap(g.runOp(ctx,
-		fmt.Sprintf("upgrade $(./gravity app-package --state-dir=.) --etcd-retry-timeout=%v", defaults.EtcdRetryTimeout)))
+	return trace.Wrap(g.runOp(ctx, upgradeArgs(flagsForVersion(g.version), false)))
+}
+
+// UpgradeManual launches upgrade operation in manual mode
+func (g *gravity) UpgradeManual(ctx context.Context) error {
+	return trace.Wrap(g.runPlanCmd(ctx, upgradeArgs(flagsForVersion(g.version), true)))
+}
+
+// Plan returns phases of the operation in progress
+func (g *gravity) Plan(ctx context.Context) ([]PlanPhase, error) {
+	var out string
+	cmd := planCmd(g.installDir, flagsForVersion(g.version))
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return nil, trace.Wrap(err, cmd)
+	}
+	if exit != 0 {
+		return nil, trace.Errorf("[%s/%s] %s returned %d",
+			g.Node().PrivateAddr(), g.Node().Addr(), cmd, exit)
+	}
+	return parsePlan([]byte(out))
+}
+
+// ExecutePhase executes phase of the operation in progress
+func (g *gravity) ExecutePhase(ctx context.Context, phase string) error {
+	return trace.Wrap(g.runPlanCmd(ctx, fmt.Sprintf("plan execute --phase=%s", phase)))
+}
+
+// exit codes of timeout(1)
+const (
+	// exitTimedOut is returned when command timed out and terminated on signal sent
+	exitTimedOut = 124
+	// exitKilled is returned when command timed out and was killed with SIGKILL
+	exitKilled = 128 + 9
+)
+
+// InterruptPhase executes phase of the operation in progress, killing it after given time.
+// It fails unless phase was still running when time ran out
+func (g *gravity) InterruptPhase(ctx context.Context, phase string, after time.Duration) error {
+	cmd := interruptCmd(g.installDir, phase, after, flagsForVersion(g.version))
+	exit, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(), cmd, nil, sshutils.ParseDiscard)
+	if err != nil {
+		return trace.Wrap(err, cmd)
+	}
+	// any other exit code means phase completed or failed before it could be interrupted
+	if exit != exitKilled && exit != exitTimedOut {
+		return trace.CompareFailed("phase %v was not interrupted, exit code %v", phase, exit)
+	}
+	g.Logger().WithFields(logrus.Fields{"phase": phase, "exit": exit}).Warn("FAULT: phase interrupted")
+	return nil
+}
+
+// RollbackPhase rolls back phase of the operation in progress
+func (g *gravity) RollbackPhase(ctx context.Context, phase string) error {
+	return trace.Wrap(g.runPlanCmd(ctx, fmt.Sprintf("plan rollback --phase=%s", phase)))
+}
+
+// CompletePlan marks operation in progress as completed
+func (g *gravity) CompletePlan(ctx context.Context) error {
+	return trace.Wrap(g.runPlanCmd(ctx, "plan complete"))
+}
+
+// runPlanCmd runs gravity command operating on plan of the operation in progress
+func (g *gravity) runPlanCmd(ctx context.Context, command string) error {
+	cmd := planOpCmd(g.installDir, command, flagsForVersion(g.version))
+	err := sshutils.Run(ctx, g.Client(), g.Logger(), cmd, nil)
+	return trace.Wrap(err, cmd)
 }
 
 // for cases when gravity doesn't return just opcode but an extended message
@@ -423,8 +718,7 @@ const (
 func (g *gravity) runOp(ctx context.Context, command string) error {
 	var code string
 	_, err := sshutils.RunAndParse(ctx, g.Client(), g.Logger(),
-		fmt.Sprintf(`cd %s && sudo ./gravity %s --insecure --quiet --system-log-file=./telekube-system.log`,
-			g.installDir, command),
+		opCmd(g.installDir, command, flagsForVersion(g.version)),
 		nil, sshutils.ParseAsString(&code))
 	if err != nil {
 		return trace.Wrap(err)
@@ -2,6 +2,7 @@ package gravity
 
 import (
 	"bufio"
+	"encoding/json"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,8 +13,13 @@ import (
 )
 
 // i.e. "Status: active"
-var rStatusKV = regexp.MustCompile(`^(?P<key>[\w\s]+)\:\s*(?P<val>[\w\d\_\-]+),*.*`)
-var rStatusNodeIp = regexp.MustCompile(`^[\s\w\-\d]+\((?P<ip>[\d\.]+)\).*`)
+var rStatusKV = regexp.MustCompile(`^(?P<key>[\w\s]+)\:\s*(?P<val>[\w\d\_\-\.]+),*.*`)
+
+// i.e. "    node-1 (10.0.0.1), Wed Jan  3 22:34 UTC" or "        * node-1 (10.0.0.1, node)"
+var rStatusNodeIp = regexp.MustCompile(`^[\s\*\w\-\d\.]+\((?P<ip>[\d\.]+)[,\)].*`)
+
+// i.e. "Application: telekube, version 5.2.3"
+var rStatusAppVersion = regexp.MustCompile(`^Application\:.*version\s+(?P<version>[\w\d\.\-\+]+)`)
 
 // parse `gravity status`
 func parseStatus(status *GravityStatus) sshutils.OutputParseFn {
@@ -21,6 +27,10 @@ func parseStatus(status *GravityStatus) sshutils.OutputParseFn {
 		scanner := bufio.NewScanner(r)
 		for scanner.Scan() {
 			line := scanner.Text()
+			if vars := rStatusAppVersion.FindStringSubmatch(line); len(vars) == 2 {
+				status.AppVersion = vars[1]
+			}
+
 			vars := rStatusKV.FindStringSubmatch(line)
 			if len(vars) == 3 {
 				populateStatus(vars[1], vars[2], status)
@@ -38,19 +48,137 @@ func parseStatus(status *GravityStatus) sshutils.OutputParseFn {
 
 func populateStatus(key, value string, status *GravityStatus) error {
 	switch key {
-	case "Cluster":
+	case "Cluster", "Cluster name":
 		status.Cluster = value
 	case "Join token":
 		status.Token = value
 	case "Application":
 		status.Application = value
-	case "Status":
+	case "Status", "Cluster status":
 		status.Status = value
 	default:
 	}
 	return nil
 }
 
+// statusJSON is a subset of `gravity status --output=json`
+type statusJSON struct {
+	Cluster *struct {
+		Application struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"application"`
+		State  string `json:"state"`
+		Reason string `json:"reason"`
+		Domain string `json:"domain"`
+		Token  struct {
+			Token string `json:"token"`
+		} `json:"token"`
+		ActiveOperations []struct {
+			ID    string `json:"id"`
+			Type  string `json:"type"`
+			State string `json:"state"`
+		} `json:"active_operations"`
+		Nodes []struct {
+			Hostname     string   `json:"hostname"`
+			AdvertiseIP  string   `json:"advertise_ip"`
+			Role         string   `json:"role"`
+			Profile      string   `json:"profile"`
+			Status       string   `json:"status"`
+			FailedProbes []string `json:"failed_probes"`
+		} `json:"nodes"`
+	} `json:"cluster"`
+}
+
+// parseStatusJSON parses `gravity status --output=json`
+func parseStatusJSON(data []byte) (*GravityStatus, error) {
+	var in statusJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, trace.Wrap(err, "%q", data)
+	}
+	if in.Cluster == nil {
+		return nil, trace.NotFound("no cluster status in %q", data)
+	}
+
+	status := &GravityStatus{
+		Application: in.Cluster.Application.Name,
+		AppVersion:  in.Cluster.Application.Version,
+		Cluster:     in.Cluster.Domain,
+		Status:      in.Cluster.State,
+		Reason:      in.Cluster.Reason,
+		Token:       in.Cluster.Token.Token,
+	}
+	for _, node := range in.Cluster.Nodes {
+		status.Nodes = append(status.Nodes, node.AdvertiseIP)
+		status.NodeStatus = append(status.NodeStatus, NodeStatus{
+			Hostname:     node.Hostname,
+			Addr:         node.AdvertiseIP,
+			Role:         node.Role,
+			Profile:      node.Profile,
+			Status:       node.Status,
+			FailedProbes: node.FailedProbes,
+		})
+	}
+	for _, op := range in.Cluster.ActiveOperations {
+		status.Operations = append(status.Operations, ClusterOperation{ID: op.ID, Type: op.Type, State: op.State})
+	}
+	return status, nil
+}
+
+// PlanPhase is a phase of operation plan
+type PlanPhase struct {
+	ID          string      `json:"id"`
+	Description string      `json:"description"`
+	State       string      `json:"state"`
+	Phases      []PlanPhase `json:"phases"`
+}
+
+const (
+	// PhaseUnstarted is state of a phase yet to be executed
+	PhaseUnstarted = "unstarted"
+	// PhaseRolledBack is state of a phase which was rolled back
+	PhaseRolledBack = "rolled_back"
+)
+
+// parsePlan parses `gravity plan --output=json`
+func parsePlan(data []byte) ([]PlanPhase, error) {
+	var plan struct {
+		Phases []PlanPhase `json:"phases"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, trace.Wrap(err, "%q", data)
+	}
+	if len(plan.Phases) == 0 {
+		return nil, trace.NotFound("no phases in operation plan %q", data)
+	}
+	return plan.Phases, nil
+}
+
+// parseDiskUsage parses output of "df" command and returns filesystem size and used space in bytes
+//
+// Example output:
+//
+// $ df --output=size,used -B1 /var/lib/gravity
+// 1B-blocks        Used
+// 42927656960  7368364032
+func parseDiskUsage(output string) (size, used uint64, err error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != 2 {
+		return 0, 0, trace.BadParameter("unexpected df output %q", output)
+	}
+
+	size, err = strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, trace.Wrap(err, "failed to parse size %q", fields[0])
+	}
+	used, err = strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, trace.Wrap(err, "failed to parse used %q", fields[1])
+	}
+	return size, used, nil
+}
+
 // from https://github.com/gravitational/gravity/blob/master/lib/utils/parse.go
 //
 // ParseDDOutput parses the output of "dd" command and returns the reported
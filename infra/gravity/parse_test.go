@@ -1,7 +1,11 @@
 package gravity
 
 import (
+	"bufio"
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +50,178 @@ func TestDDOutputParser(t *testing.T) {
 		assert.Equal(t, bps, testCase.expectedBps, testCase.comment)
 	}
 }
+
+func TestDiskUsageParser(t *testing.T) {
+	size, used, err := parseDiskUsage(`   1B-blocks        Used
+ 42927656960  7368364032
+`)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42927656960), size)
+	assert.Equal(t, uint64(7368364032), used)
+
+	size, used, err = parseDiskUsage(`sudo: unable to resolve host node-0
+   1B-blocks Used
+ 1000 0`)
+	require.NoError(t, err, "ignores unrelevant parts")
+	assert.Equal(t, uint64(1000), size)
+	assert.Equal(t, uint64(0), used)
+
+	_, _, err = parseDiskUsage("df: /nonexistent: No such file or directory")
+	assert.Error(t, err)
+}
+
+// statusNodes are nodes of the cluster in captured status outputs in testdata
+var statusNodes = []string{"10.0.1.10", "10.0.1.11", "10.0.1.12"}
+
+func TestParseStatusText(t *testing.T) {
+	var testCases = []struct {
+		file     string
+		expected GravityStatus
+	}{
+		{
+			file: "status-4.x.txt",
+			expected: GravityStatus{
+				Application: "telekube",
+				AppVersion:  "4.68.0",
+				Cluster:     "robotest-cluster",
+				Status:      "active",
+				Token:       "4f1e2d0c5b6a",
+				Nodes:       statusNodes,
+			},
+		},
+		{
+			file: "status-5.x.txt",
+			expected: GravityStatus{
+				Application: "telekube",
+				AppVersion:  "5.2.3",
+				Cluster:     "robotest-cluster",
+				Status:      "degraded",
+				Token:       "4f1e2d0c5b6a",
+				Nodes:       statusNodes,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		f, err := os.Open(filepath.Join("testdata", testCase.file))
+		require.NoError(t, err)
+		defer f.Close()
+
+		var status GravityStatus
+		err = parseStatus(&status)(bufio.NewReader(f))
+		require.NoError(t, err, testCase.file)
+		assert.Equal(t, testCase.expected, status, testCase.file)
+	}
+}
+
+func TestParseStatusJSON(t *testing.T) {
+	healthy := func(hostname, addr, role, profile string) NodeStatus {
+		return NodeStatus{Hostname: hostname, Addr: addr, Role: role, Profile: profile, Status: "healthy"}
+	}
+
+	var testCases = []struct {
+		file     string
+		expected GravityStatus
+	}{
+		{
+			file: "status-5.x.json",
+			expected: GravityStatus{
+				Application: "telekube",
+				AppVersion:  "5.5.8",
+				Cluster:     "robotest-cluster",
+				Status:      "active",
+				Token:       "4f1e2d0c5b6a",
+				Nodes:       statusNodes,
+				NodeStatus: []NodeStatus{
+					healthy("robotest-node-0", "10.0.1.10", "master", "node"),
+					healthy("robotest-node-1", "10.0.1.11", "master", "node"),
+					healthy("robotest-node-2", "10.0.1.12", "node", "worker"),
+				},
+			},
+		},
+		{
+			file: "status-6.x.json",
+			expected: GravityStatus{
+				Application: "telekube",
+				AppVersion:  "6.1.4",
+				Cluster:     "robotest-cluster",
+				Status:      "updating",
+				Token:       "4f1e2d0c5b6a",
+				Nodes:       statusNodes,
+				NodeStatus: []NodeStatus{
+					healthy("robotest-node-0", "10.0.1.10", "master", "node"),
+					healthy("robotest-node-1", "10.0.1.11", "master", "node"),
+					healthy("robotest-node-2", "10.0.1.12", "node", "worker"),
+				},
+				Operations: []ClusterOperation{{
+					ID:    "3c1b8e2a-5d4f-4a6b-8c7d-9e0f1a2b3c4d",
+					Type:  "operation_update",
+					State: "update_in_progress",
+				}},
+			},
+		},
+		{
+			file: "status-7.x.json",
+			expected: GravityStatus{
+				Application: "telekube",
+				AppVersion:  "7.0.12",
+				Cluster:     "robotest-cluster",
+				Status:      "degraded",
+				Reason:      "one or more nodes are offline",
+				Token:       "4f1e2d0c5b6a",
+				Nodes:       statusNodes,
+				NodeStatus: []NodeStatus{
+					healthy("robotest-node-0", "10.0.1.10", "master", "node"),
+					healthy("robotest-node-1", "10.0.1.11", "master", "node"),
+					{Hostname: "robotest-node-2", Addr: "10.0.1.12", Role: "node", Profile: "worker", Status: "offline",
+						FailedProbes: []string{"node is offline"}},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", testCase.file))
+		require.NoError(t, err)
+
+		status, err := parseStatusJSON(data)
+		require.NoError(t, err, testCase.file)
+		assert.Equal(t, &testCase.expected, status, testCase.file)
+	}
+
+	_, err := parseStatusJSON([]byte(`{"agent": {}}`))
+	assert.Error(t, err, "no cluster status")
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "status-5.x.txt"))
+	require.NoError(t, err)
+	_, err = parseStatusJSON(data)
+	assert.Error(t, err, "older versions print text")
+}
+
+func TestParsePlan(t *testing.T) {
+	phases, err := parsePlan([]byte(`{
+  "operation_id": "3c1b",
+  "operation_type": "operation_update",
+  "cluster_name": "dev.local",
+  "phases": [
+    {"id": "/init", "description": "Initialize update operation", "state": "completed",
+     "phases": [{"id": "/init/node-1", "description": "Initialize node node-1", "state": "completed"}]},
+    {"id": "/checks", "description": "Run preflight checks", "state": "completed"},
+    {"id": "/masters", "description": "Update master nodes", "state": "in_progress",
+     "phases": [{"id": "/masters/node-1", "description": "Update system software on master node node-1", "state": "failed"}]},
+    {"id": "/gc", "description": "Run cleanup tasks", "state": "unstarted"}
+  ]
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, []PlanPhase{
+		{ID: "/init", Description: "Initialize update operation", State: "completed",
+			Phases: []PlanPhase{{ID: "/init/node-1", Description: "Initialize node node-1", State: "completed"}}},
+		{ID: "/checks", Description: "Run preflight checks", State: "completed"},
+		{ID: "/masters", Description: "Update master nodes", State: "in_progress",
+			Phases: []PlanPhase{{ID: "/masters/node-1", Description: "Update system software on master node node-1", State: "failed"}}},
+		{ID: "/gc", Description: "Run cleanup tasks", State: PhaseUnstarted},
+	}, phases)
+
+	_, err = parsePlan([]byte(`{"operation_id": "3c1b"}`))
+	assert.Error(t, err, "no phases")
+}
@@ -0,0 +1,76 @@
+package gravity
+
+import (
+	"github.com/gravitational/robotest/infra"
+	"github.com/gravitational/robotest/infra/ops"
+	"github.com/gravitational/robotest/infra/power"
+	"github.com/gravitational/robotest/infra/terraform"
+	"github.com/gravitational/robotest/lib/constants"
+)
+
+// powerController returns power controller for the cloud provider used.
+// Providers without power API, i.e. local runs, get a simulated controller:
+// nodes keep running, so only the control flow of tests is exercised
+func (c *TestContext) powerController() (power.Controller, error) {
+	cfg := c.provisionerCfg
+	switch cfg.CloudProvider {
+	case constants.Ops:
+		return power.NewAWS(power.AWSConfig{
+			AccessKey: cfg.Ops.EC2AccessKey,
+			SecretKey: cfg.Ops.EC2SecretKey,
+			Region:    cfg.Ops.EC2Region,
+		})
+	case constants.AWS:
+		return power.NewAWS(power.AWSConfig{
+			AccessKey: cfg.AWS.AccessKey,
+			SecretKey: cfg.AWS.SecretKey,
+			Region:    cfg.AWS.Region,
+		})
+	case constants.Azure:
+		return power.NewAzure(power.AzureConfig{
+			AzureAuthParam: terraform.AzureAuthParam{
+				ClientId:     cfg.Azure.ClientId,
+				ClientSecret: cfg.Azure.ClientSecret,
+				TenantId:     cfg.Azure.TenantId,
+			},
+			SubscriptionId: cfg.Azure.SubscriptionId,
+			ResourceGroup:  cfg.Tag(),
+		})
+	default:
+		c.Logger().WithField("provider", cfg.CloudProvider).Warn("power control is simulated")
+		return power.NewLocal(), nil
+	}
+}
+
+// withPowerControl makes nodes power state manageable via cloud provider API, or simulated one
+func (c *TestContext) withPowerControl(nodes []infra.Node, params cloudDynamicParams) []infra.Node {
+	ctrl, err := c.powerController()
+	if err != nil {
+		c.Logger().WithError(err).Warn("nodes will not support power control")
+		return nodes
+	}
+
+	sshUser, sshKeyPath := sshCredentials(params)
+	out := make([]infra.Node, 0, len(nodes))
+	for _, node := range nodes {
+		privateAddr := node.PrivateAddr()
+		out = append(out, power.New(node, ctrl, func(publicAddr string) infra.Node {
+			return ops.New(publicAddr, privateAddr, sshUser, sshKeyPath)
+		}))
+	}
+	return out
+}
+
+// sshCredentials returns SSH user and key path to access nodes
+func sshCredentials(params cloudDynamicParams) (user, keyPath string) {
+	switch params.CloudProvider {
+	case constants.Ops:
+		return params.Ops.SSHUser, params.Ops.SSHKeyPath
+	case constants.AWS:
+		return params.user, params.AWS.SSHKeyPath
+	case constants.Azure:
+		return params.user, params.Azure.SSHKeyPath
+	default:
+		return params.user, ""
+	}
+}
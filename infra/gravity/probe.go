@@ -0,0 +1,258 @@
+package gravity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ProbeAPIServer is kube-apiserver probe target
+	ProbeAPIServer = "kube-apiserver"
+	// ProbeService is sample workload node port service probe target
+	ProbeService = "service"
+)
+
+// Outage is a period of time a probe target was unavailable
+type Outage struct {
+	Start time.Time `json:"start"`
+	// End is zero while outage is still in progress
+	End time.Time `json:"end"`
+}
+
+// AvailabilityReport summarizes outages of a probe target between two checkpoints
+type AvailabilityReport struct {
+	// Checkpoint is name of the operation the report covers
+	Checkpoint string `json:"checkpoint"`
+	// Target is probe target, see ProbeXXX constants
+	Target string `json:"target"`
+	// Period is time elapsed since previous checkpoint
+	Period time.Duration `json:"period"`
+	// Downtime is total time target was unavailable
+	Downtime time.Duration `json:"downtime"`
+	// LongestOutage is the longest period target was continuously unavailable
+	LongestOutage time.Duration `json:"longest_outage"`
+	// Outages is number of times target became unavailable
+	Outages int `json:"outages"`
+}
+
+// String returns human readable report
+func (r AvailabilityReport) String() string {
+	return fmt.Sprintf("%v %v: downtime %v, longest outage %v, %d outage(s) in %v",
+		r.Checkpoint, r.Target, r.Downtime, r.LongestOutage, r.Outages, r.Period)
+}
+
+// probeTarget is checked by running command on the probing node,
+// target is unavailable when command exits with non-zero status
+type probeTarget struct {
+	name string
+	cmd  string
+}
+
+// Probe continuously checks availability of cluster endpoints in background
+type Probe struct {
+	c        *TestContext
+	from     Gravity
+	targets  []probeTarget
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	sync.Mutex
+	// since is when the last checkpoint was made
+	since time.Time
+	// outages are outages per target since the last checkpoint
+	outages map[string][]Outage
+}
+
+// StartProbe starts probing kube-apiserver on nodes, and sample workload service
+// if workload is not nil, from node via SSH at fixed interval until test completes.
+// Probe results are reported with Checkpoint
+func (c *TestContext) StartProbe(from Gravity, nodes []Gravity, workload *Workload, interval time.Duration) (*Probe, error) {
+	if len(nodes) == 0 {
+		return nil, trace.BadParameter("node list empty")
+	}
+	if interval <= 0 {
+		return nil, trace.BadParameter("probe interval should be positive, got %v", interval)
+	}
+
+	addrs := []string{}
+	for _, node := range nodes {
+		addrs = append(addrs, node.Node().PrivateAddr())
+	}
+	targets := []probeTarget{{
+		name: ProbeAPIServer,
+		cmd: fmt.Sprintf(`for ip in %s; do code=$(curl -sk -o /dev/null -w '%%{http_code}' --max-time %d https://$ip:6443/healthz); `+
+			`[ "$code" -gt 0 ] && [ "$code" -lt 500 ] && exit 0; done; exit 1`,
+			strings.Join(addrs, " "), probeTimeoutSec(interval)),
+	}}
+	if workload != nil {
+		targets = append(targets, probeTarget{
+			name: ProbeService,
+			cmd: fmt.Sprintf(`for ip in %s; do curl -sf -o /dev/null --max-time %d http://$ip:%s/data && exit 0; done; exit 1`,
+				strings.Join(addrs, " "), probeTimeoutSec(interval), workload.NodePort),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(c.parent)
+	p := &Probe{
+		c:        c,
+		from:     from,
+		targets:  targets,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		since:    time.Now(),
+		outages:  make(map[string][]Outage),
+	}
+
+	c.Logger().WithFields(logrus.Fields{"from": from, "interval": interval.String()}).Info("start availability probe")
+	go p.run(ctx)
+	c.OnTeardown("stop availability probe", func(ctx context.Context) error {
+		p.Stop()
+		return nil
+	})
+	return p, nil
+}
+
+// Checkpoint reports availability of probe targets since previous checkpoint
+// or probe start, reports are also logged and added to test status
+func (p *Probe) Checkpoint(name string) []AvailabilityReport {
+	p.Lock()
+	now := time.Now()
+	reports := []AvailabilityReport{}
+	for _, target := range p.targets {
+		outages := p.outages[target.name]
+		reports = append(reports, summarizeOutages(name, target.name, outages, p.since, now))
+		// outage in progress carries over into the next period
+		p.outages[target.name] = nil
+		if len(outages) != 0 && outages[len(outages)-1].End.IsZero() {
+			p.outages[target.name] = []Outage{{Start: now}}
+		}
+	}
+	p.since = now
+	p.Unlock()
+
+	for _, report := range reports {
+		p.c.Logger().WithField("availability", report).Info(report.String())
+	}
+	p.c.addAvailability(reports)
+	return reports
+}
+
+// Stop stops the probe, it is safe to call it multiple times
+func (p *Probe) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+func (p *Probe) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, target := range p.targets {
+				p.check(ctx, target)
+			}
+		}
+	}
+}
+
+// check runs target command and records outage start or end.
+// Failure to reach probing node itself, i.e. while it is offline, is not considered an outage
+func (p *Probe) check(ctx context.Context, target probeTarget) {
+	ctx, cancel := context.WithTimeout(ctx, probeCheckTimeout)
+	defer cancel()
+
+	log := p.from.Logger().WithField("probe", target.name)
+	client := p.from.Client()
+	if client == nil {
+		log.Debug("availability probe failed: probing node is offline")
+		return
+	}
+
+	now := time.Now()
+	exit, err := sshutils.RunAndParse(ctx, client, log, target.cmd, nil, sshutils.ParseDiscard)
+	if err != nil {
+		log.WithError(err).Debug("availability probe failed")
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.outages[target.name] = recordOutage(p.outages[target.name], exit == 0, now)
+}
+
+// recordOutage opens a new outage when target becomes unavailable
+// and closes outage in progress when target becomes available again
+func recordOutage(outages []Outage, available bool, now time.Time) []Outage {
+	inProgress := len(outages) != 0 && outages[len(outages)-1].End.IsZero()
+	switch {
+	case available && inProgress:
+		outages[len(outages)-1].End = now
+	case !available && !inProgress:
+		outages = append(outages, Outage{Start: now})
+	}
+	return outages
+}
+
+// summarizeOutages reports outages within from..to period,
+// outage still in progress is considered to end at to
+func summarizeOutages(checkpoint, target string, outages []Outage, from, to time.Time) AvailabilityReport {
+	report := AvailabilityReport{Checkpoint: checkpoint, Target: target, Period: to.Sub(from)}
+	for _, outage := range outages {
+		end := outage.End
+		if end.IsZero() || end.After(to) {
+			end = to
+		}
+		start := outage.Start
+		if start.Before(from) {
+			start = from
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		duration := end.Sub(start)
+		report.Outages++
+		report.Downtime += duration
+		if duration > report.LongestOutage {
+			report.LongestOutage = duration
+		}
+	}
+	return report
+}
+
+// Availability returns availability probe reports collected so far
+func (c *TestContext) Availability() []AvailabilityReport {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	return c.availability
+}
+
+func (c *TestContext) addAvailability(reports []AvailabilityReport) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.availability = append(c.availability, reports...)
+}
+
+// probeTimeoutSec is how long to wait for target to respond, in seconds
+func probeTimeoutSec(interval time.Duration) int {
+	if interval < 2*time.Second {
+		return 1
+	}
+	return int(interval/time.Second) / 2
+}
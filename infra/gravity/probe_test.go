@@ -0,0 +1,49 @@
+package gravity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutages(t *testing.T) {
+	start := time.Date(2018, 1, 3, 22, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	var outages []Outage
+	outages = recordOutage(outages, true, at(1))
+	assert.Empty(t, outages, "available target")
+
+	outages = recordOutage(outages, false, at(2))
+	outages = recordOutage(outages, false, at(3))
+	outages = recordOutage(outages, true, at(7))
+	outages = recordOutage(outages, false, at(10))
+	outages = recordOutage(outages, true, at(11))
+	outages = recordOutage(outages, false, at(20))
+	assert.Equal(t, []Outage{
+		{Start: at(2), End: at(7)},
+		{Start: at(10), End: at(11)},
+		{Start: at(20)},
+	}, outages)
+
+	report := summarizeOutages("upgrade", ProbeAPIServer, outages, at(0), at(30))
+	assert.Equal(t, AvailabilityReport{
+		Checkpoint:    "upgrade",
+		Target:        ProbeAPIServer,
+		Period:        30 * time.Second,
+		Downtime:      16 * time.Second,
+		LongestOutage: 10 * time.Second,
+		Outages:       3,
+	}, report, "outage in progress ends at checkpoint")
+
+	report = summarizeOutages("expand", ProbeService, outages, at(5), at(15))
+	assert.Equal(t, AvailabilityReport{
+		Checkpoint:    "expand",
+		Target:        ProbeService,
+		Period:        10 * time.Second,
+		Downtime:      3 * time.Second,
+		LongestOutage: 2 * time.Second,
+		Outages:       2,
+	}, report, "outages are clipped to period")
+}
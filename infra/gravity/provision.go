@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
@@ -18,6 +17,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/gravitational/robotest/infra"
+	"github.com/gravitational/robotest/infra/ops"
 	"github.com/gravitational/robotest/infra/terraform"
 	"github.com/gravitational/robotest/lib/constants"
 	sshutil "github.com/gravitational/robotest/lib/ssh"
@@ -113,9 +113,9 @@ func (c *TestContext) provisionOps(cfg ProvisionerConfig) ([]Gravity, DestroyFn,
 		return nil, nil, trace.Wrap(err)
 	}
 	c.Logger().Debug("logging into the ops center")
-	out, err := exec.Command("tele", "login", "-o", cfg.Ops.URL, "--key", cfg.Ops.OpsKey).CombinedOutput()
+	client, err := opsLogin(c.Context(), cfg.Ops)
 	if err != nil {
-		return nil, nil, trace.WrapWithMessage(err, string(out))
+		return nil, nil, trace.Wrap(err)
 	}
 
 	// generate a random cluster name
@@ -143,16 +143,20 @@ func (c *TestContext) provisionOps(cfg ProvisionerConfig) ([]Gravity, DestroyFn,
 	if err != nil {
 		return nil, nil, trace.Wrap(err)
 	}
+	cluster, err := ops.ParseCluster([]byte(defn))
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
 
 	// next, we need to tell the ops center to create our cluster
 	c.Logger().Debug("requesting ops center to provision our cluster")
-	out, err = exec.Command("tele", "create", clusterPath).CombinedOutput()
+	err = client.CreateCluster(c.Context(), *cluster)
 	if err != nil {
-		return nil, nil, trace.Wrap(err, string(out))
+		return nil, nil, trace.Wrap(err)
 	}
 
 	// destroyFn defines the clean up function that will destroy provisioned resources
-	destroyFn := cfg.DestroyOpsFn(c, clusterName)
+	destroyFn := cfg.DestroyOpsFn(c, client, clusterName)
 
 	// monitor the cluster until it's created or times out
 	timeout := time.After(cloudInitTimeout)
@@ -166,16 +170,16 @@ Loop:
 			return nil, destroyFn, errors.New("clusterInitTimeout exceeded")
 		case <-tick:
 			// check provisioning status
-			status, err := getTeleClusterStatus(clusterName)
+			status, err := client.GetClusterStatus(c.Context(), clusterName)
 			c.Logger().WithField("status", status).Debug("provisioning status")
 			if err != nil {
 				return nil, destroyFn, trace.Wrap(err)
 			}
 
 			switch status {
-			case "installing":
+			case ops.ClusterStatusInstalling:
 				// we're still installing, just continue the loop
-			case "active":
+			case ops.ClusterStatusActive:
 				// the cluster install completed, we can continue the install process
 				break Loop
 			default:
@@ -237,11 +241,15 @@ func (c *TestContext) provisionCloud(cfg ProvisionerConfig) (gravityNodes []Grav
 		}
 	}()
 
+	// cloud resources are named after tag of the successful provisioning attempt
+	c.provisionerCfg.tag = params.Tag()
+	c.provisionerCfg.scalingGroup = params.scalingGroup
+
 	ctx, cancel := context.WithTimeout(c.Context(), cloudInitTimeout)
 	defer cancel()
 
 	c.Logger().Debug("Configuring VMs")
-	gravityNodes, err = configureVMs(ctx, c.Logger(), *params, nodes)
+	gravityNodes, err = configureVMs(ctx, c.Logger(), *params, c.withPowerControl(nodes, *params))
 	if err != nil {
 		c.Logger().WithError(err).Error("Some nodes failed to initialize, tear down as non-usable.")
 		return nil, nil, trace.Wrap(err)
@@ -283,10 +291,18 @@ func (c *TestContext) postProvision(cfg ProvisionerConfig, gravityNodes []Gravit
 	for _, node := range gravityNodes {
 		timeNodes = append(timeNodes, sshutil.SshNode{node.Client(), node.Logger()})
 	}
-	if err := sshutil.WaitTimeSync(ctx, timeNodes); err != nil {
+	err := sshutil.WaitTimeSync(ctx, timeNodes)
+	if err == nil {
+		return nil
+	}
+	if !cfg.FixTimeSync {
 		return trace.Wrap(err)
 	}
-	return nil
+
+	c.Logger().WithError(err).Warn("clocks not synchronized, will set them explicitly")
+	ctx, cancel = context.WithTimeout(c.Context(), c.timeouts.Fault)
+	defer cancel()
+	return trace.Wrap(sshutil.FixTimeSync(ctx, timeNodes))
 }
 
 // sort Interface implementation
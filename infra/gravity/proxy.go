@@ -0,0 +1,167 @@
+package gravity
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+
+	"github.com/gravitational/trace"
+)
+
+const (
+	// proxyPort is the port forward proxy listens on
+	proxyPort = 3128
+	// proxyAccessLog is where forward proxy logs requests
+	proxyAccessLog = "/var/log/squid/access.log"
+	// gravityEnvironmentFile is sourced on nodes before running gravity install and join
+	gravityEnvironmentFile = "/tmp/gravity_environment"
+)
+
+// proxyConfig allows cluster nodes to use squid as forward proxy, %s is ACL of node addresses
+const proxyConfig = `http_port %d
+acl cluster src %s
+acl SSL_ports port 443
+acl CONNECT method CONNECT
+http_access deny CONNECT !SSL_ports
+http_access allow localhost
+http_access allow cluster
+http_access deny all
+access_log %s squid
+`
+
+// Proxy is forward HTTP proxy running on a node
+type Proxy struct {
+	// Node is where proxy runs
+	Node Gravity
+	// URL is proxy address for cluster nodes
+	URL string
+}
+
+// StartProxy installs and starts squid forward proxy on the node, which nodes could use
+func (c *TestContext) StartProxy(node Gravity, nodes []Gravity) (*Proxy, error) {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Install)
+	defer cancel()
+
+	addrs := []string{}
+	for _, n := range nodes {
+		addrs = append(addrs, n.Node().PrivateAddr())
+	}
+	config := fmt.Sprintf(proxyConfig, proxyPort, strings.Join(addrs, " "), proxyAccessLog)
+
+	node.Logger().WithField("port", proxyPort).Info("start forward proxy")
+	err := sshutils.RunCommands(ctx, node.Client(), node.Logger(), []sshutils.Cmd{
+		{Command: "(command -v apt-get && sudo apt-get update -q && sudo apt-get install -y -q squid) || sudo yum install -y -q squid"},
+		{Command: fmt.Sprintf("printf '%s' | sudo tee /etc/squid/squid.conf", config)},
+		{Command: "sudo systemctl restart squid && sudo systemctl is-active squid"},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &Proxy{
+		Node: node,
+		URL:  fmt.Sprintf("http://%s:%d", node.Node().PrivateAddr(), proxyPort),
+	}, nil
+}
+
+// UseProxy makes install and join on nodes pass proxy variables to gravity,
+// exports them in gravity environment file on every node,
+// and blocks direct egress from nodes so they can only reach the internet via the proxy
+func (c *TestContext) UseProxy(nodes []Gravity, proxy *Proxy) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	noProxy := []string{"localhost", "127.0.0.1", ".local", ".cluster.local"}
+	for _, node := range nodes {
+		noProxy = append(noProxy, node.Node().PrivateAddr())
+	}
+	env := proxyEnv(proxy.URL, noProxy)
+
+	for _, node := range nodes {
+		g, ok := node.(*gravity)
+		if !ok {
+			return trace.BadParameter("unexpected node type %T", node)
+		}
+		merged, err := sudoEnv(g.env, env)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = sshutils.Run(ctx, node.Client(), node.Logger(), exportEnvCmd(gravityEnvironmentFile, env), nil)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		g.env = merged
+	}
+
+	return trace.Wrap(c.AirGap(nodes, proxy.Node))
+}
+
+// exportEnvCmd returns command replacing exports of env variables in file
+func exportEnvCmd(file string, env map[string]string) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exports := make([]string, 0, len(names))
+	for _, name := range names {
+		exports = append(exports, fmt.Sprintf("export %s=%s", name, shellQuote(env[name])))
+	}
+	return fmt.Sprintf("sudo touch %[1]s && sudo sed -i -E '/^export (%[2]s)=/d' %[1]s && cat <<'EOF' | sudo tee -a %[1]s > /dev/null\n%[3]s\nEOF",
+		file, strings.Join(names, "|"), strings.Join(exports, "\n"))
+}
+
+// proxyEnv returns environment of gravity using proxyURL
+func proxyEnv(proxyURL string, noProxy []string) map[string]string {
+	env := map[string]string{}
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		env[name] = proxyURL
+	}
+	for _, name := range []string{"NO_PROXY", "no_proxy"} {
+		env[name] = strings.Join(noProxy, ",")
+	}
+	return env
+}
+
+// CheckProxied verifies proxy has served requests from any of the nodes
+func (c *TestContext) CheckProxied(proxy *Proxy, nodes []Gravity) error {
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	var out string
+	_, err := sshutils.RunAndParse(ctx, proxy.Node.Client(), proxy.Node.Logger(),
+		fmt.Sprintf("sudo cat %s", proxyAccessLog), nil, sshutils.ParseAsString(&out))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	requests := parseProxyLog(out)
+	c.Logger().WithField("requests", requests).Info("proxy requests by client")
+
+	for _, node := range nodes {
+		if requests[node.Node().PrivateAddr()] != 0 {
+			return nil
+		}
+	}
+	return trace.CompareFailed("no requests via proxy from %v", nodes)
+}
+
+// parseProxyLog counts requests per client address in squid native access log,
+// where each line is "timestamp elapsed client code/status bytes method URL ..."
+func parseProxyLog(log string) map[string]int {
+	requests := map[string]int{}
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 || strings.HasPrefix(fields[0], "sudo:") {
+			continue
+		}
+		requests[fields[2]]++
+	}
+	return requests
+}
@@ -0,0 +1,42 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProxyLog(t *testing.T) {
+	log := `sudo: unable to resolve host robotest-proxy
+1540000000.123    512 10.0.1.10 TCP_MISS/200 3146 CONNECT registry-1.docker.io:443 - HIER_DIRECT/34.205.207.96 -
+1540000001.456     87 10.0.1.10 TCP_MISS/200 1520 CONNECT auth.docker.io:443 - HIER_DIRECT/52.5.11.128 -
+1540000002.789      0 10.0.1.11 TCP_DENIED/403 3924 GET http://example.com/ - HIER_NONE/- text/html
+
+`
+	assert.Equal(t, map[string]int{
+		"10.0.1.10": 2,
+		"10.0.1.11": 1,
+	}, parseProxyLog(log))
+}
+
+func TestProxyEnv(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"HTTP_PROXY":  "http://10.0.1.5:3128",
+		"HTTPS_PROXY": "http://10.0.1.5:3128",
+		"http_proxy":  "http://10.0.1.5:3128",
+		"https_proxy": "http://10.0.1.5:3128",
+		"NO_PROXY":    "localhost,10.0.1.10",
+		"no_proxy":    "localhost,10.0.1.10",
+	}, proxyEnv("http://10.0.1.5:3128", []string{"localhost", "10.0.1.10"}))
+}
+
+func TestExportEnvCmd(t *testing.T) {
+	cmd := exportEnvCmd("/tmp/gravity_environment", map[string]string{
+		"NO_PROXY":   "localhost,10.0.1.10",
+		"HTTP_PROXY": "http://10.0.1.5:3128",
+	})
+	assert.Equal(t, `sudo touch /tmp/gravity_environment && sudo sed -i -E '/^export (HTTP_PROXY|NO_PROXY)=/d' /tmp/gravity_environment && cat <<'EOF' | sudo tee -a /tmp/gravity_environment > /dev/null
+export HTTP_PROXY='http://10.0.1.5:3128'
+export NO_PROXY='localhost,10.0.1.10'
+EOF`, cmd)
+}
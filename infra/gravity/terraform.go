@@ -52,6 +52,8 @@ func wrapDestroyFn(c *TestContext, tag string, nodes []Gravity, destroy func(con
 			}
 		}()
 
+		c.teardown()
+
 		log := c.Logger().WithFields(logrus.Fields{
 			"nodes":              nodes,
 			"provisioner_policy": policy,
@@ -73,6 +75,10 @@ func wrapDestroyFn(c *TestContext, tag string, nodes []Gravity, destroy func(con
 			defer cancel()
 		}
 
+		if !skipLogCollection && c.Failed() {
+			c.logHealth(nodes)
+		}
+
 		if !skipLogCollection && (c.Failed() || policy.AlwaysCollectLogs) {
 			log.Debug("collecting logs from nodes...")
 			err := c.CollectLogs("postmortem", nodes)
@@ -233,7 +239,7 @@ func runTerraform(ctx context.Context, baseConfig ProvisionerConfig, logger logr
 		if err != nil {
 			return wait.Abort(trace.Wrap(err))
 		}
-		nodes, destroyFn, err = runTerraformOnce(ctx, cfg, *params)
+		nodes, destroyFn, err = runTerraformOnce(ctx, cfg, params)
 
 		if err == nil {
 			return nil
@@ -250,7 +256,8 @@ func runTerraform(ctx context.Context, baseConfig ProvisionerConfig, logger logr
 }
 
 // terraform deals with underlying terraform provisioner
-func runTerraformOnce(baseContext context.Context, baseConfig ProvisionerConfig, params cloudDynamicParams) ([]infra.Node, func(context.Context) error, error) {
+// provisioned scaling group, if any, is recorded in params
+func runTerraformOnce(baseContext context.Context, baseConfig ProvisionerConfig, params *cloudDynamicParams) ([]infra.Node, func(context.Context) error, error) {
 	// there's an internal retry in provisioners,
 	// however they get stuck sometimes and the only real way to deal with it is to kill and retry
 	// as they'll pick up incomplete state from cloud and proceed
@@ -282,6 +289,7 @@ func runTerraformOnce(baseContext context.Context, baseConfig ProvisionerConfig,
 		}
 
 		resourceAllocated(baseConfig.Tag())
+		params.scalingGroup = p.State().ScalingGroup
 		return p.NodePool().Nodes(), p.Destroy, nil
 	}
 
@@ -3,6 +3,8 @@ package gravity
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gravitational/robotest/lib/xlog"
@@ -21,7 +23,7 @@ const (
 // whether test must be failed
 // provisioner has its own timeout / restart logic which is dependant on cloud provider and terraform
 type OpTimeouts struct {
-	Install, Upgrade, Status, Uninstall, Leave, CollectLogs, WaitForInstaller, AutoScaling time.Duration
+	Install, Upgrade, Status, Uninstall, Leave, CollectLogs, WaitForInstaller, AutoScaling, Fault, Reboot, Backup time.Duration
 }
 
 // TestContext aggregates common parameters for better test suite readability
@@ -38,6 +40,32 @@ type TestContext struct {
 	logLink        string
 	status         string
 	provisionerCfg ProvisionerConfig
+
+	teardownMu sync.Mutex
+	// teardownFns are invoked once the test completes, i.e. to undo injected faults
+	teardownFns []teardownFn
+	// netFaults keeps track of nodes with network faults injected
+	netFaults networkFaults
+
+	healthMu sync.Mutex
+	// health is the last collected cluster health report
+	health *HealthReport
+	// availability are availability probe reports per checkpoint
+	availability []AvailabilityReport
+	// timings are durations of test steps to report in test results
+	timings []Timing
+}
+
+// Timing is how long a named test step took
+type Timing struct {
+	Name    string        `json:"name"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// teardownFn is a named function to invoke on test teardown
+type teardownFn struct {
+	name string
+	fn   func(ctx context.Context) error
 }
 
 // Run allows a running test to spawn a subtest
@@ -91,6 +119,77 @@ func (c *TestContext) OK(msg string, err error) {
 	c.log.WithFields(fields).Info(msg)
 }
 
+// ErrorMatcher reports whether err is the expected failure
+type ErrorMatcher func(err error) bool
+
+// AnyError matches any failure
+func AnyError(err error) bool {
+	return true
+}
+
+// ErrorContains matches failure whose error message, which includes command output, contains all substrings.
+// Comparison is case-insensitive
+func ErrorContains(substrings ...string) ErrorMatcher {
+	return func(err error) bool {
+		message := strings.ToLower(err.Error() + " " + trace.UserMessage(err))
+		for _, substring := range substrings {
+			if !strings.Contains(message, strings.ToLower(substring)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ExpectFailure marks milestone which is expected to fail:
+// test continues if err is not nil and matches, and fails otherwise
+func (c *TestContext) ExpectFailure(msg string, err error, match ErrorMatcher) {
+	if err == nil {
+		c.OK(msg, trace.CompareFailed("expected failure, but operation succeeded"))
+	}
+	if !match(err) {
+		c.OK(msg, trace.CompareFailed("unexpected failure: %v", err))
+	}
+
+	c.log.WithError(err).Info("expected failure")
+	c.OK(msg, nil)
+}
+
+// OnTeardown registers fn to be invoked once when test completes,
+// before logs are collected and resources are destroyed.
+// Functions are invoked in reverse order of registration
+func (c *TestContext) OnTeardown(name string, fn func(ctx context.Context) error) {
+	c.teardownMu.Lock()
+	defer c.teardownMu.Unlock()
+
+	c.teardownFns = append(c.teardownFns, teardownFn{name, fn})
+}
+
+// teardown invokes registered teardown functions, logging their errors
+// it uses own timeout as test context might be already cancelled
+func (c *TestContext) teardown() {
+	c.teardownMu.Lock()
+	fns := c.teardownFns
+	c.teardownFns = nil
+	c.teardownMu.Unlock()
+
+	if len(fns) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), finalTeardownTimeout)
+	defer cancel()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		log := c.log.WithField("teardown", fns[i].name)
+		if err := fns[i].fn(ctx); err != nil {
+			log.WithError(err).Error("teardown failed")
+			continue
+		}
+		log.Info("teardown completed")
+	}
+}
+
 // FailNow requests this test suite to abort
 func (c *TestContext) FailNow() {
 	if c.err == nil {
@@ -117,6 +216,22 @@ func (c *TestContext) Sleep(msg string, d time.Duration) {
 	}
 }
 
+// RecordTiming logs how long a test step took and records it to be reported in test results
+func (c *TestContext) RecordTiming(name string, elapsed time.Duration) {
+	c.log.WithFields(logrus.Fields{"step": name, "elapsed": elapsed.String()}).Info("step completed")
+
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.timings = append(c.timings, Timing{Name: name, Elapsed: elapsed})
+}
+
+// Timings returns durations of test steps recorded so far
+func (c *TestContext) Timings() []Timing {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	return c.timings
+}
+
 func withDuration(d time.Duration, n int) time.Duration {
 	return d * time.Duration(n)
 }
@@ -0,0 +1,17 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorContains(t *testing.T) {
+	err := trace.Wrap(trace.Errorf("sudo ./gravity join returned 255: [ERROR]: Access denied: bad token"), "joining")
+
+	assert.True(t, ErrorContains("access denied")(err))
+	assert.True(t, ErrorContains("ACCESS DENIED", "token")(err))
+	assert.False(t, ErrorContains("access denied", "disk space")(err))
+	assert.True(t, AnyError(err))
+}
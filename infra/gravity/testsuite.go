@@ -56,6 +56,12 @@ type TestStatus struct {
 	Status        string
 	LogUrl        string
 	Param         interface{}
+	// Health is the last collected cluster health report, if any
+	Health *HealthReport
+	// Availability are availability probe reports per checkpoint, if any
+	Availability []AvailabilityReport
+	// Timings are durations of test steps, if any were recorded
+	Timings []Timing
 }
 
 // testRun logically groups multiple test runs for centralized progress and status reporting
@@ -250,6 +256,7 @@ func (s *testSuite) runTestFunc(t *testing.T, fn TestFunc, cfg ProvisionerConfig
 
 	defer func() {
 		r := recover()
+		cx.teardown()
 		if r == nil {
 			cx.updateStatus(TestStatusPassed)
 			return
@@ -302,12 +309,15 @@ func (s *testSuite) Run() []TestStatus {
 	status := []TestStatus{}
 	for _, test := range s.tests {
 		status = append(status, TestStatus{
-			Name:     test.name,
-			Status:   test.status,
-			Param:    test.param,
-			UID:      test.uid,
-			SuiteUID: test.suite.uid,
-			LogUrl:   test.logLink,
+			Name:         test.name,
+			Status:       test.status,
+			Param:        test.param,
+			UID:          test.uid,
+			SuiteUID:     test.suite.uid,
+			LogUrl:       test.logLink,
+			Health:       test.Health(),
+			Availability: test.Availability(),
+			Timings:      test.Timings(),
 		})
 	}
 	return status
@@ -0,0 +1,257 @@
+package gravity
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gravitational/robotest/lib/defaults"
+
+	"github.com/gravitational/trace"
+	semver "github.com/hashicorp/go-version"
+)
+
+// commandFlags are gravity command line features which differ across gravity releases
+type commandFlags struct {
+	// SystemLogFile is whether commands accept --system-log-file
+	SystemLogFile bool
+	// EtcdRetryTimeout is whether upgrade accepts --etcd-retry-timeout
+	EtcdRetryTimeout bool
+}
+
+// versionFlags maps gravity version ranges to supported command flags, first matching range wins.
+// Flags of the last range are used when version is unknown
+var versionFlags = []struct {
+	constraint semver.Constraints
+	flags      commandFlags
+}{
+	{mustConstraint("< 5.0.0"), commandFlags{}},
+	{mustConstraint(">= 5.0.0, < 5.2.0"), commandFlags{SystemLogFile: true}},
+	{mustConstraint(">= 5.2.0"), commandFlags{SystemLogFile: true, EtcdRetryTimeout: true}},
+}
+
+func mustConstraint(constraint string) semver.Constraints {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// flagsForVersion returns command flags supported by gravity version, nil version is considered latest.
+// Pre-release versions get flags of their release
+func flagsForVersion(version *semver.Version) commandFlags {
+	if version == nil {
+		return versionFlags[len(versionFlags)-1].flags
+	}
+
+	segments := append(version.Segments(), 0, 0, 0)
+	release, err := semver.NewVersion(fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2]))
+	if err != nil {
+		return versionFlags[len(versionFlags)-1].flags
+	}
+	for _, v := range versionFlags {
+		if v.constraint.Check(release) {
+			return v.flags
+		}
+	}
+	return versionFlags[len(versionFlags)-1].flags
+}
+
+// rVersion matches gravity version in `gravity version` output, i.e. "Version:	5.2.3"
+var rVersion = regexp.MustCompile(`(?m)^Version:\s+v?(\S+)`)
+
+// parseVersion parses gravity version from `gravity version` output
+func parseVersion(out string) (*semver.Version, error) {
+	match := rVersion.FindStringSubmatch(out)
+	if len(match) != 2 {
+		return nil, trace.NotFound("no version in %q", out)
+	}
+	version, err := semver.NewVersion(match[1])
+	if err != nil {
+		return nil, trace.BadParameter("unexpected version %q: %v", match[1], err)
+	}
+	return version, nil
+}
+
+// systemLogFile is where gravity commands log to, relative to installer dir
+const systemLogFile = "./telekube-system.log"
+
+// installCmd specify additional configuration for the install command
+// collected from defaults and/or computed values
+type installCmd struct {
+	InstallDir      string
+	PrivateAddr     string
+	EnvDockerDevice string
+	StorageDriver   string
+	LicenseFile     string
+	K8SConfigFile   string
+	SystemLogFile   string
+	// SudoEnv is environment passed to gravity through sudo
+	SudoEnv map[string]string
+	Flags   commandFlags
+	InstallParam
+}
+
+var installCmdTemplate = template.Must(
+	template.New("gravity_install").Funcs(cmdFuncs).Parse(`
+		source /tmp/gravity_environment >/dev/null 2>&1 || true; \
+		cd {{.InstallDir}} && ./gravity version && sudo {{range $name, $value := .SudoEnv}}{{$name}}={{quote $value}} {{end}}./gravity install --debug \
+		--advertise-addr={{.PrivateAddr}} --token={{.Token}} --flavor={{.Flavor}} \
+		--docker-device=${{.EnvDockerDevice}} \
+		{{if .StorageDriver}}--storage-driver={{.StorageDriver}}{{end}} \
+		{{if .Flags.SystemLogFile}}--system-log-file={{.SystemLogFile}}{{end}} \
+		--cloud-provider={{.CloudProvider}} --state-dir={{.StateDir}} \
+		{{if .Cluster}}--cluster={{.Cluster}}{{end}} \
+		{{if .PodNetworkCIDR}}--pod-network-cidr={{.PodNetworkCIDR}}{{end}} \
+		{{if .ServiceCIDR}}--service-cidr={{.ServiceCIDR}}{{end}} \
+		{{if .K8SConfigFile}}--config={{.K8SConfigFile}}{{end}} \
+		{{if .LicenseFile}}--license="$(cat {{.LicenseFile}})"{{end}} \
+		{{range .ExtraArgs}}{{.}} {{end}}
+`))
+
+// joinCmd specify additional configuration for the join command
+// collected from defaults and/or computed values
+type joinCmd struct {
+	InstallDir, PrivateAddr, EnvDockerDevice, SystemLogFile string
+	// SudoEnv is environment passed to gravity through sudo
+	SudoEnv map[string]string
+	Flags   commandFlags
+	JoinCmd
+}
+
+var joinCmdTemplate = template.Must(
+	template.New("gravity_join").Funcs(cmdFuncs).Parse(`
+		source /tmp/gravity_environment >/dev/null 2>&1 || true; \
+		cd {{.InstallDir}} && sudo {{range $name, $value := .SudoEnv}}{{$name}}={{quote $value}} {{end}}./gravity join {{.PeerAddr}} \
+		--advertise-addr={{.PrivateAddr}} --token={{.Token}} --debug \
+		--role={{.Role}} --docker-device=${{.EnvDockerDevice}} \
+		{{if .Flags.SystemLogFile}}--system-log-file={{.SystemLogFile}}{{end}} --state-dir={{.StateDir}}`))
+
+// cmdFuncs are helpers available to command templates
+var cmdFuncs = template.FuncMap{"quote": shellQuote}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// rEnvName matches valid environment variable name
+var rEnvName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// sudoEnv merges environments to pass through sudo, later ones take precedence
+func sudoEnv(envs ...map[string]string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, env := range envs {
+		for name, value := range env {
+			if !rEnvName.MatchString(name) {
+				return nil, trace.BadParameter("invalid environment variable name %q", name)
+			}
+			out[name] = value
+		}
+	}
+	return out, nil
+}
+
+// renderCmd executes command template with param
+func renderCmd(tmpl *template.Template, param interface{}) (string, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, param)
+	if err != nil {
+		return "", trace.Wrap(err, buf.String())
+	}
+	return buf.String(), nil
+}
+
+// gravityCmd returns command running gravity with args followed by flags supported by gravity version.
+// Installed gravity is used if installDir is empty, otherwise the one in installDir
+func gravityCmd(installDir string, flags commandFlags, args ...string) string {
+	args = append(args, flagArgs(flags)...)
+	if installDir == "" {
+		return fmt.Sprintf(`sudo gravity %s`, strings.Join(args, " "))
+	}
+	return fmt.Sprintf(`cd %s && sudo ./gravity %s`, installDir, strings.Join(args, " "))
+}
+
+// flagArgs returns arguments common to gravity commands, as supported by gravity version
+func flagArgs(flags commandFlags) []string {
+	if flags.SystemLogFile {
+		return []string{fmt.Sprintf("--system-log-file=%s", systemLogFile)}
+	}
+	return nil
+}
+
+// opCmd returns command launching gravity operation from installDir
+func opCmd(installDir, command string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, command, "--insecure", "--quiet")
+}
+
+// statusCmd returns command querying cluster status, in JSON if json is set
+func statusCmd(flags commandFlags, json bool) string {
+	if json {
+		return gravityCmd("", flags, "status", "--output=json")
+	}
+	return gravityCmd("", flags, "status")
+}
+
+// uninstallCmd returns command removing gravity installation from the node
+func uninstallCmd(installDir string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, "system", "uninstall", "--confirm")
+}
+
+// backupCmd returns command creating application backup in file
+func backupCmd(installDir, file string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, "system", "backup", file)
+}
+
+// restoreCmd returns command restoring application backup from file
+func restoreCmd(installDir, file string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, "system", "restore", file)
+}
+
+// planCmd returns command displaying plan of the operation in progress in JSON
+func planCmd(installDir string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, "plan", "--output=json")
+}
+
+// planOpCmd returns gravity command operating on plan of the operation in progress, i.e. "plan complete"
+func planOpCmd(installDir, command string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, command, "--insecure")
+}
+
+// interruptCmd returns command executing phase of the operation in progress, killed after given time
+func interruptCmd(installDir, phase string, after time.Duration, flags commandFlags) string {
+	args := append([]string{"plan", "execute", fmt.Sprintf("--phase=%s", phase), "--insecure"}, flagArgs(flags)...)
+	return fmt.Sprintf(`cd %s && sudo timeout --signal=KILL %d ./gravity %s`,
+		installDir, int(after.Seconds()), strings.Join(args, " "))
+}
+
+// upgradeArgs returns arguments of gravity upgrade with application package of current installer
+func upgradeArgs(flags commandFlags, manual bool) string {
+	args := []string{"upgrade"}
+	if manual {
+		args = append(args, "--manual")
+	}
+	args = append(args, "$(./gravity app-package --state-dir=.)")
+	if flags.EtcdRetryTimeout {
+		args = append(args, fmt.Sprintf("--etcd-retry-timeout=%v", defaults.EtcdRetryTimeout))
+	}
+	return strings.Join(args, " ")
+}
+
+// updateUploadCmd returns command uploading packages of installer in installDir to the local cluster
+func updateUploadCmd(installDir string, flags commandFlags) string {
+	return gravityCmd(installDir, flags, "update", "upload")
+}
+
+// updateArgs returns arguments of gravity update to application package of current installer
+func updateArgs(flags commandFlags) string {
+	args := []string{"update", "trigger", "$(./gravity app-package --state-dir=.)"}
+	if flags.EtcdRetryTimeout {
+		args = append(args, fmt.Sprintf("--etcd-retry-timeout=%v", defaults.EtcdRetryTimeout))
+	}
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,196 @@
+package gravity
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravitational/trace"
+	semver "github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files of command templates in testdata")
+
+func TestParseVersion(t *testing.T) {
+	out := `sudo: unable to resolve host node-1
+Edition:	open-source
+Version:	5.2.3
+Git Commit:	3a6a3b6e5ae2f8d0d34b9d5e0b0ba21e4b4c3a9c
+Helm Version:	v2.11.0
+`
+	version, err := parseVersion(out)
+	require.NoError(t, err)
+	assert.Equal(t, "5.2.3", version.String())
+
+	version, err = parseVersion("Version:\tv5.3.0-alpha.1\n")
+	require.NoError(t, err)
+	assert.Equal(t, "5.3.0-alpha.1", version.String())
+
+	_, err = parseVersion("command not found")
+	assert.Error(t, err)
+}
+
+func TestFlagsForVersion(t *testing.T) {
+	legacy := commandFlags{}
+	v50 := commandFlags{SystemLogFile: true}
+	latest := commandFlags{SystemLogFile: true, EtcdRetryTimeout: true}
+
+	for _, tt := range []struct {
+		version string
+		flags   commandFlags
+	}{
+		{"4.68.0", legacy},
+		{"5.0.0", v50},
+		{"5.0.35", v50},
+		{"5.2.0-alpha.3", latest},
+		{"5.2.3", latest},
+		{"6.0.0", latest},
+		{"", latest},
+	} {
+		assert.Equal(t, tt.flags, flagsForVersion(testVersion(t, tt.version)), tt.version)
+	}
+}
+
+func TestCommandTemplates(t *testing.T) {
+	install := InstallParam{
+		Token:          "ROBOTEST",
+		Role:           "node",
+		Cluster:        "robotest-cluster",
+		Flavor:         "three",
+		PodNetworkCIDR: "10.200.0.0/16",
+		ServiceCIDR:    "10.201.0.0/16",
+		CloudProvider:  "generic",
+		StateDir:       "/var/lib/gravity",
+		ExtraArgs:      []string{"--wizard=false"},
+		Env:            map[string]string{"GRAVITY_DEBUG": "true", "ROBOTEST_NOTE": "it's $HOME"},
+	}
+	join := JoinCmd{
+		PeerAddr: "10.0.1.10",
+		Token:    "ROBOTEST",
+		Role:     "worker",
+		StateDir: "/var/lib/gravity",
+	}
+
+	proxy := proxyEnv("http://10.0.1.5:3128", []string{"localhost", "10.0.1.10", "10.0.1.11"})
+	installEnv, err := sudoEnv(proxy, install.Env)
+	require.NoError(t, err)
+
+	for _, version := range []string{"4.68.0", "5.0.35", "5.2.3", ""} {
+		flags := flagsForVersion(testVersion(t, version))
+		name := version
+		if name == "" {
+			name = "unknown"
+		}
+
+		cmd, err := renderCmd(installCmdTemplate, installCmd{
+			InstallDir:      "/home/robotest/install",
+			PrivateAddr:     "10.0.1.10",
+			EnvDockerDevice: "ROBO_DOCKER_DEVICE",
+			StorageDriver:   "overlay2",
+			LicenseFile:     "/home/robotest/install/license.pem",
+			K8SConfigFile:   "/home/robotest/install/resources.yaml",
+			SystemLogFile:   systemLogFile,
+			SudoEnv:         installEnv,
+			Flags:           flags,
+			InstallParam:    install,
+		})
+		require.NoError(t, err)
+		assertGolden(t, fmt.Sprintf("install-%s.golden", name), cmd)
+
+		cmd, err = renderCmd(joinCmdTemplate, joinCmd{
+			InstallDir:      "/home/robotest/install",
+			PrivateAddr:     "10.0.1.11",
+			EnvDockerDevice: "ROBO_DOCKER_DEVICE",
+			SystemLogFile:   systemLogFile,
+			SudoEnv:         proxy,
+			Flags:           flags,
+			JoinCmd:         join,
+		})
+		require.NoError(t, err)
+		assertGolden(t, fmt.Sprintf("join-%s.golden", name), cmd)
+	}
+}
+
+func TestOpCommands(t *testing.T) {
+	legacy := commandFlags{}
+	latest := commandFlags{SystemLogFile: true, EtcdRetryTimeout: true}
+
+	assert.Equal(t, "cd /install && sudo ./gravity leave --confirm --insecure --quiet",
+		opCmd("/install", "leave --confirm", legacy))
+	assert.Equal(t, "cd /install && sudo ./gravity leave --confirm --insecure --quiet --system-log-file=./telekube-system.log",
+		opCmd("/install", "leave --confirm", latest))
+
+	assert.Equal(t, "upgrade $(./gravity app-package --state-dir=.)", upgradeArgs(legacy, false))
+	assert.Equal(t, "upgrade --manual $(./gravity app-package --state-dir=.) --etcd-retry-timeout=5m0s",
+		upgradeArgs(latest, true))
+	assert.Equal(t, "update trigger $(./gravity app-package --state-dir=.)", updateArgs(legacy))
+
+	for _, version := range []string{"4.68.0", "5.0.35", "5.2.3", ""} {
+		flags := flagsForVersion(testVersion(t, version))
+		name := version
+		if name == "" {
+			name = "unknown"
+		}
+
+		dir := "/home/robotest/install"
+		cmds := []string{
+			statusCmd(flags, true),
+			statusCmd(flags, false),
+			opCmd(dir, "leave --confirm", flags),
+			uninstallCmd(dir, flags),
+			backupCmd(dir, "/tmp/backup.tar.gz", flags),
+			restoreCmd(dir, "/tmp/backup.tar.gz", flags),
+			planCmd(dir, flags),
+			planOpCmd(dir, upgradeArgs(flags, true), flags),
+			planOpCmd(dir, "plan execute --phase=/init", flags),
+			planOpCmd(dir, "plan complete", flags),
+			interruptCmd(dir, "/masters", 30*time.Second, flags),
+			updateUploadCmd(dir, flags),
+			opCmd(dir, updateArgs(flags), flags),
+		}
+		assertGolden(t, fmt.Sprintf("commands-%s.golden", name), strings.Join(cmds, "\n")+"\n")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'true'`, shellQuote("true"))
+	assert.Equal(t, `''`, shellQuote(""))
+	assert.Equal(t, `'it'\''s $HOME "quoted"'`, shellQuote(`it's $HOME "quoted"`))
+}
+
+func TestSudoEnv(t *testing.T) {
+	env, err := sudoEnv(map[string]string{"A": "1", "B": "2"}, nil, map[string]string{"B": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "3"}, env)
+
+	_, err = sudoEnv(map[string]string{"A B": "1"})
+	assert.True(t, trace.IsBadParameter(err), "%v", err)
+}
+
+// testVersion parses version, empty version is unknown
+func testVersion(t *testing.T, version string) *semver.Version {
+	if version == "" {
+		return nil
+	}
+	v, err := semver.NewVersion(version)
+	require.NoError(t, err)
+	return v
+}
+
+// assertGolden compares actual output with golden file in testdata, updating it if requested
+func assertGolden(t *testing.T, name, actual string) {
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		require.NoError(t, ioutil.WriteFile(path, []byte(actual), 0644))
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual, name)
+}
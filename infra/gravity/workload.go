@@ -0,0 +1,369 @@
+package gravity
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	sshutils "github.com/gravitational/robotest/lib/ssh"
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+	"github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// workloadName is name of all Kubernetes resources of the sample workload
+	workloadName = "robotest-workload"
+	// workloadLabel selects sample workload pods
+	workloadLabel = "app=" + workloadName
+	// workloadImage is default sample workload image, it must provide sh and httpd
+	workloadImage = "busybox:1.30"
+	// workloadVolumeDir is where local volume is created when no storage class is given
+	workloadVolumeDir = "/var/lib/gravity/" + workloadName
+	// planetShareHostDir is shared with Planet container as planetShareDir
+	planetShareHostDir = "/var/lib/gravity/planet/share"
+	planetShareDir     = "/ext/share"
+)
+
+// WorkloadParam defines sample stateful application
+// used to verify that data and service endpoints survive cluster operations
+type WorkloadParam struct {
+	// Image is container image serving data over HTTP, must provide sh and httpd
+	Image string `json:"image"`
+	// StorageClass is persistent volume claim storage class,
+	// local volume on a single node is used when empty
+	StorageClass string `json:"storage_class"`
+}
+
+// Workload is sample stateful application deployed into the cluster
+type Workload struct {
+	WorkloadParam
+	// Namespace is Kubernetes namespace workload is deployed into
+	Namespace string
+	// Node holds local volume when no storage class is given
+	Node string
+	// Data is written to the volume once on deploy and is expected to stay intact
+	Data string
+	// NodePort is port workload service is exposed on every node
+	NodePort string
+}
+
+// DeployWorkload deploys sample stateful application: a job writes known data
+// to a persistent volume which is then served over HTTP by a deployment behind a node port service.
+// Unless storage class is given, volume is local to node (first node if nil),
+// which therefore has to stay within the cluster
+func (c *TestContext) DeployWorkload(nodes []Gravity, node Gravity, param WorkloadParam) (*Workload, error) {
+	if len(nodes) == 0 {
+		return nil, trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	if param.Image == "" {
+		param.Image = workloadImage
+	}
+	if node == nil {
+		node = nodes[0]
+	}
+	w := &Workload{
+		WorkloadParam: param,
+		Namespace:     "default",
+		Node:          node.Node().PrivateAddr(),
+		Data:          uuid.NewV4().String(),
+	}
+
+	manifest, err := w.manifest()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	master := nodes[0]
+	log := c.Logger().WithFields(logrus.Fields{"workload": workloadName, "node": w.Node})
+	log.Info("deploy workload")
+
+	if w.StorageClass == "" {
+		err = sshutils.Run(ctx, node.Client(), node.Logger(), "sudo mkdir -p "+workloadVolumeDir, nil)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
+	file := workloadName + ".yaml"
+	cmd := fmt.Sprintf("sudo mkdir -p %[1]s && cat <<'EOF' | sudo tee %[1]s/%[2]s > /dev/null\n%[3]s\nEOF",
+		planetShareHostDir, file, manifest)
+	err = sshutils.Run(ctx, master.Client(), master.Logger(), cmd, nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	_, err = master.RunInPlanet(ctx, "/usr/bin/kubectl", "apply", "-f", path.Join(planetShareDir, file))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	err = wait.Retry(ctx, func() error {
+		out, err := master.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "job", workloadName,
+			"-n", w.Namespace, `-ojsonpath='{.status.succeeded}'`)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		if strings.TrimSpace(out) != "1" {
+			return wait.Continue("workload data not written yet")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, trace.Wrap(err, "writing workload data")
+	}
+
+	out, err := master.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "service", workloadName,
+		"-n", w.Namespace, `-ojsonpath='{.spec.ports[0].nodePort}'`)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	w.NodePort = strings.TrimSpace(out)
+
+	return w, trace.Wrap(c.verifyWorkload(ctx, nodes, w))
+}
+
+// VerifyWorkload waits for sample application to become available
+// and verifies data written on deploy is served intact via its service
+func (c *TestContext) VerifyWorkload(nodes []Gravity, w *Workload) error {
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	return trace.Wrap(c.verifyWorkload(ctx, nodes, w))
+}
+
+// DeleteWorkload deletes Kubernetes resources of sample application
+// and wipes its data from local volume on the node
+func (c *TestContext) DeleteWorkload(nodes []Gravity, w *Workload) error {
+	if len(nodes) == 0 {
+		return trace.BadParameter("node list empty")
+	}
+
+	ctx, cancel := context.WithTimeout(c.parent, c.timeouts.Status)
+	defer cancel()
+
+	resources := "deployment,service,job,pvc"
+	if w.StorageClass == "" {
+		resources += ",pv"
+	}
+	_, err := nodes[0].RunInPlanet(ctx, "/usr/bin/kubectl", "delete", resources, "-n", w.Namespace,
+		"-l", workloadLabel, "--ignore-not-found")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if w.StorageClass == "" {
+		node, err := nodeByAddr(nodes, w.Node)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = sshutils.Run(ctx, node.Client(), node.Logger(), "sudo rm -rf "+workloadVolumeDir, nil)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
+	c.Logger().WithField("workload", workloadName).Info("workload deleted")
+	return nil
+}
+
+func (c *TestContext) verifyWorkload(ctx context.Context, nodes []Gravity, w *Workload) error {
+	master := nodes[0]
+	now := time.Now()
+
+	retry := wait.Retryer{
+		Attempts:    1000,
+		Delay:       time.Second * 10,
+		FieldLogger: c.Logger().WithField("checkpoint", "workload"),
+	}
+
+	err := retry.Do(ctx, func() error {
+		pods, err := KubectlGetPods(ctx, master, w.Namespace, workloadLabel)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		ready := false
+		for _, pod := range pods {
+			ready = ready || pod.Ready
+		}
+		if !ready {
+			return wait.Continue(fmt.Sprintf("no ready %v pods in %v", workloadName, pods))
+		}
+
+		addr, err := master.RunInPlanet(ctx, "/usr/bin/kubectl", "get", "service", workloadName,
+			"-n", w.Namespace, `-ojsonpath='{.spec.clusterIP}'`)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+
+		data, err := master.RunInPlanet(ctx, "/usr/bin/curl", "-sf",
+			fmt.Sprintf("http://%v/data", strings.TrimSpace(addr)))
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		data = strings.TrimSpace(data)
+		if data == "" {
+			return wait.Continue("no workload data served")
+		}
+		if data != w.Data {
+			return wait.Abort(trace.CompareFailed("workload data %q, expected %q", data, w.Data))
+		}
+		return nil
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	c.Logger().WithFields(logrus.Fields{
+		"workload": workloadName, "elapsed": time.Since(now).String(),
+	}).Info("workload data intact")
+	return nil
+}
+
+// nodeByAddr returns node with the given private address
+func nodeByAddr(nodes []Gravity, addr string) (Gravity, error) {
+	for _, node := range nodes {
+		if node.Node().PrivateAddr() == addr {
+			return node, nil
+		}
+	}
+	return nil, trace.NotFound("no node with address %v", addr)
+}
+
+func (w *Workload) manifest() (string, error) {
+	var buf bytes.Buffer
+	err := workloadTemplate.Execute(&buf, struct {
+		*Workload
+		Name, Dir string
+	}{w, workloadName, workloadVolumeDir})
+	return buf.String(), trace.Wrap(err)
+}
+
+// workloadTemplate defines sample workload, a host path persistent volume
+// pinned to the node is created unless storage class is given
+var workloadTemplate = template.Must(template.New("workload").Parse(`{{if not .StorageClass}}apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+spec:
+  capacity:
+    storage: 100Mi
+  accessModes: ["ReadWriteOnce"]
+  persistentVolumeReclaimPolicy: Retain
+  storageClassName: {{.Name}}
+  hostPath:
+    path: {{.Dir}}
+  nodeAffinity:
+    required:
+      nodeSelectorTerms:
+      - matchExpressions:
+        - key: kubernetes.io/hostname
+          operator: In
+          values: ["{{.Node}}"]
+---
+{{end}}apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+  namespace: {{.Namespace}}
+spec:
+  accessModes: ["ReadWriteOnce"]
+  storageClassName: {{if .StorageClass}}{{.StorageClass}}{{else}}{{.Name}}{{end}}
+  resources:
+    requests:
+      storage: 100Mi
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+  namespace: {{.Namespace}}
+spec:
+  template:
+    spec:
+      restartPolicy: OnFailure
+      containers:
+      - name: writer
+        image: {{.Image}}
+        command: ["sh", "-c", "echo {{.Data}} > /data/data"]
+        volumeMounts:
+        - name: data
+          mountPath: /data
+      volumes:
+      - name: data
+        persistentVolumeClaim:
+          claimName: {{.Name}}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+  namespace: {{.Namespace}}
+spec:
+  replicas: 1
+  strategy:
+    type: Recreate
+  selector:
+    matchLabels:
+      app: {{.Name}}
+  template:
+    metadata:
+      labels:
+        app: {{.Name}}
+    spec:
+      containers:
+      - name: server
+        image: {{.Image}}
+        command: ["httpd", "-f", "-p", "8080", "-h", "/data"]
+        ports:
+        - containerPort: 8080
+        readinessProbe:
+          httpGet:
+            path: /data
+            port: 8080
+        volumeMounts:
+        - name: data
+          mountPath: /data
+          readOnly: true
+      volumes:
+      - name: data
+        persistentVolumeClaim:
+          claimName: {{.Name}}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{.Name}}
+  labels:
+    app: {{.Name}}
+  namespace: {{.Namespace}}
+spec:
+  type: NodePort
+  selector:
+    app: {{.Name}}
+  ports:
+  - port: 80
+    targetPort: 8080
+`))
@@ -0,0 +1,30 @@
+package gravity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkloadManifest(t *testing.T) {
+	w := &Workload{
+		WorkloadParam: WorkloadParam{Image: workloadImage},
+		Namespace:     "default",
+		Node:          "10.0.0.1",
+		Data:          "c0ffee",
+	}
+
+	manifest, err := w.manifest()
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "kind: PersistentVolume\n")
+	assert.Contains(t, manifest, `values: ["10.0.0.1"]`)
+	assert.Contains(t, manifest, "storageClassName: "+workloadName)
+	assert.Contains(t, manifest, `"echo c0ffee > /data/data"`)
+
+	w.StorageClass = "openebs-hostpath"
+	manifest, err = w.manifest()
+	require.NoError(t, err)
+	assert.NotContains(t, manifest, "kind: PersistentVolume\n")
+	assert.Contains(t, manifest, "storageClassName: openebs-hostpath")
+}
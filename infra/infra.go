@@ -141,6 +141,18 @@ type Node interface {
 	Client() (*ssh.Client, error)
 }
 
+// PowerNode is a node which power state is managed via cloud provider API
+type PowerNode interface {
+	Node
+	// PowerOff forcibly powers off the node
+	PowerOff(ctx context.Context) error
+	// PowerOn powers on the node previously powered off.
+	// Node public address may change
+	PowerOn(ctx context.Context) error
+	// HardReset forcibly powers the node off and on again
+	HardReset(ctx context.Context) error
+}
+
 var defaultLogger = log.New()
 
 // Distribute executes the specified command on given nodes
@@ -0,0 +1,90 @@
+package ops
+
+import (
+	"github.com/go-yaml/yaml"
+	"github.com/gravitational/trace"
+)
+
+const (
+	// ClusterStatusInstalling means the cluster is being provisioned and installed
+	ClusterStatusInstalling = "installing"
+	// ClusterStatusActive means the cluster is installed and healthy
+	ClusterStatusActive = "active"
+	// ClusterStatusUninstalling means the cluster is being de-provisioned
+	ClusterStatusUninstalling = "uninstalling"
+	// ClusterStatusFailed means the last cluster operation has failed
+	ClusterStatusFailed = "failed"
+)
+
+// Cluster is a minimal copy of the Cluster resource from the gravitational/gravity project
+// Unneeded fields have been removed, and only required fields are included in the local copy
+// https://github.com/gravitational/gravity/blob/20cfcef8d50ab403f0a9452376ccd52e145ae90c/lib/storage/cluster.go#L65
+type Cluster struct {
+	// Kind is the resource kind, always "cluster"
+	Kind string `json:"kind" yaml:"kind"`
+	// Version is the resource version
+	Version string `json:"version" yaml:"version"`
+	// Metadata contains cluster name and labels
+	Metadata Metadata `json:"metadata" yaml:"metadata"`
+	// Spec contains cluster specification
+	Spec ClusterSpec `json:"spec" yaml:"spec"`
+}
+
+// Metadata is resource metadata
+type Metadata struct {
+	// Name is the cluster name
+	Name string `json:"name" yaml:"name"`
+	// Labels is a set of cluster labels
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// ClusterSpec is cluster V2 specification from gravitational/gravity project
+// it is a minimal copy of only needed fields
+type ClusterSpec struct {
+	// App is the application package to install
+	App string `json:"app" yaml:"app"`
+	// AWS defines AWS specific provisioning parameters
+	AWS *ClusterAWS `json:"aws,omitempty" yaml:"aws,omitempty"`
+	// Nodes defines node profiles and their counts
+	Nodes []ClusterNodes `json:"nodes" yaml:"nodes"`
+	// Provider is the cloud provider
+	Provider string `json:"provider" yaml:"provider"`
+	// Status is a cluster status, initialized for existing clusters only
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// ClusterAWS defines AWS specific cluster parameters
+type ClusterAWS struct {
+	// KeyName is the name of the SSH key pair
+	KeyName string `json:"keyName" yaml:"keyName"`
+	// Region is the AWS region
+	Region string `json:"region" yaml:"region"`
+}
+
+// ClusterNodes defines a group of nodes of the same profile
+type ClusterNodes struct {
+	// Profile is the node profile from the application manifest
+	Profile string `json:"profile" yaml:"profile"`
+	// Count is the number of nodes
+	Count int `json:"count" yaml:"count"`
+	// InstanceType is the cloud instance type
+	InstanceType string `json:"instanceType" yaml:"instanceType"`
+}
+
+// ParseCluster parses cluster resource in YAML or JSON format
+func ParseCluster(data []byte) (*Cluster, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing cluster data")
+	}
+
+	var cluster Cluster
+	err := yaml.Unmarshal(data, &cluster)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	if cluster.Metadata.Name == "" {
+		return nil, trace.BadParameter("missing cluster name")
+	}
+	return &cluster, nil
+}
@@ -0,0 +1,17 @@
+package ops
+
+import (
+	"context"
+)
+
+// Operator manages clusters provisioned by Ops Center
+type Operator interface {
+	// CreateCluster requests Ops Center to provision and install a new cluster
+	CreateCluster(ctx context.Context, cluster Cluster) error
+	// GetClusterStatus returns cluster status, or trace.NotFound if there's no such cluster
+	GetClusterStatus(ctx context.Context, name string) (string, error)
+	// DeleteCluster requests Ops Center to uninstall and de-provision the cluster
+	DeleteCluster(ctx context.Context, name string) error
+}
+
+var _ Operator = (*Tele)(nil)
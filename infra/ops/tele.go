@@ -0,0 +1,100 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/gravitational/trace"
+)
+
+// Tele manages Ops Center clusters by invoking `tele` binary
+type Tele struct{}
+
+// TeleLogin logs `tele` into Ops Center with API key
+func TeleLogin(ctx context.Context, opsURL, key string) (*Tele, error) {
+	out, err := exec.CommandContext(ctx, "tele", "login", "-o", opsURL, "--key", key).CombinedOutput()
+	if err != nil {
+		return nil, trace.WrapWithMessage(err, string(out))
+	}
+	return &Tele{}, nil
+}
+
+// CreateCluster requests Ops Center to provision and install a new cluster with `tele create`
+func (t *Tele) CreateCluster(ctx context.Context, cluster Cluster) error {
+	data, err := yaml.Marshal(cluster)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	f, err := ioutil.TempFile("", "cluster")
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	f.Close()
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+
+	out, err := exec.CommandContext(ctx, "tele", "create", f.Name()).CombinedOutput()
+	if err != nil {
+		return trace.WrapWithMessage(err, string(out))
+	}
+	return nil
+}
+
+// GetClusterStatus returns cluster status from `tele get clusters`
+func (t *Tele) GetClusterStatus(ctx context.Context, name string) (string, error) {
+	out, err := exec.CommandContext(ctx, "tele", "get", "clusters", name, "--format", "yaml").CombinedOutput()
+	if err != nil {
+		if isClusterNotFound(name, out) {
+			return "", trace.NotFound("cluster %v not found", name)
+		}
+		return "", trace.WrapWithMessage(err, string(out))
+	}
+
+	status, err := parseClusterStatus(name, out)
+	if err != nil {
+		return "", trace.WrapWithMessage(err, string(out))
+	}
+	return status, nil
+}
+
+// DeleteCluster requests Ops Center to uninstall and de-provision the cluster with `tele rm cluster`
+func (t *Tele) DeleteCluster(ctx context.Context, name string) error {
+	out, err := exec.CommandContext(ctx, "tele", "rm", "cluster", name).CombinedOutput()
+	if err != nil {
+		return trace.WrapWithMessage(err, string(out))
+	}
+	return nil
+}
+
+func isClusterNotFound(name string, out []byte) bool {
+	return strings.Contains(string(out), fmt.Sprintf("cluster %v not found", name))
+}
+
+// parseClusterStatus will attempt to unmarshal the cluster status from tele get clusters output
+func parseClusterStatus(name string, data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", trace.BadParameter("missing cluster data")
+	}
+
+	if isClusterNotFound(name, data) {
+		return "", trace.NotFound("cluster not found")
+	}
+
+	cluster := Cluster{}
+	err := yaml.Unmarshal(data, &cluster)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	return cluster.Spec.Status, nil
+}
@@ -0,0 +1,126 @@
+package power
+
+import (
+	"context"
+
+	"github.com/gravitational/trace"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AWSConfig defines EC2 connection parameters
+type AWSConfig struct {
+	// AccessKey http://docs.aws.amazon.com/general/latest/gr/managing-aws-access-keys.html
+	AccessKey string
+	// SecretKey http://docs.aws.amazon.com/general/latest/gr/managing-aws-access-keys.html
+	SecretKey string
+	// Region is EC2 region of instances
+	Region string
+}
+
+type awsController struct {
+	ec2 *ec2.EC2
+}
+
+// NewAWS returns Controller managing EC2 instances
+func NewAWS(cfg AWSConfig) (Controller, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(cfg.Region),
+		Credentials: credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return &awsController{ec2: ec2.New(sess)}, nil
+}
+
+// Stop forcibly stops the instance
+func (r *awsController) Stop(ctx context.Context, privateAddr string) error {
+	instance, err := r.instance(ctx, privateAddr)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	ids := []*string{instance.InstanceId}
+	_, err = r.ec2.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{
+		InstanceIds: ids,
+		Force:       aws.Bool(true),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = r.ec2.WaitUntilInstanceStoppedWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: ids})
+	return trace.Wrap(err)
+}
+
+// Start starts the instance once it is stopped
+func (r *awsController) Start(ctx context.Context, privateAddr string) (string, error) {
+	instance, err := r.instance(ctx, privateAddr)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	ids := []*string{instance.InstanceId}
+	input := &ec2.DescribeInstancesInput{InstanceIds: ids}
+	switch aws.StringValue(instance.State.Name) {
+	case ec2.InstanceStateNameRunning:
+		return aws.StringValue(instance.PublicIpAddress), nil
+	case ec2.InstanceStateNameStopping:
+		// i.e. powered off from within the instance
+		if err = r.ec2.WaitUntilInstanceStoppedWithContext(ctx, input); err != nil {
+			return "", trace.Wrap(err)
+		}
+	}
+
+	_, err = r.ec2.StartInstancesWithContext(ctx, &ec2.StartInstancesInput{InstanceIds: ids})
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	if err = r.ec2.WaitUntilInstanceRunningWithContext(ctx, input); err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	// public address is assigned on every start
+	instance, err = r.instance(ctx, privateAddr)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return aws.StringValue(instance.PublicIpAddress), nil
+}
+
+// instance looks up not terminated instance by private address
+func (r *awsController) instance(ctx context.Context, privateAddr string) (*ec2.Instance, error) {
+	resp, err := r.ec2.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("private-ip-address"),
+				Values: []*string{aws.String(privateAddr)},
+			},
+			{
+				Name: aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{
+					ec2.InstanceStateNamePending,
+					ec2.InstanceStateNameRunning,
+					ec2.InstanceStateNameStopping,
+					ec2.InstanceStateNameStopped,
+				}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	for _, reservation := range resp.Reservations {
+		for _, instance := range reservation.Instances {
+			return instance, nil
+		}
+	}
+	return nil, trace.NotFound("no instance with private address %v", privateAddr)
+}
@@ -0,0 +1,156 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/robotest/infra/azure"
+	"github.com/gravitational/robotest/infra/terraform"
+	"github.com/gravitational/robotest/lib/wait"
+
+	"github.com/gravitational/trace"
+)
+
+// AzureConfig defines Azure resource group connection parameters
+type AzureConfig struct {
+	terraform.AzureAuthParam
+	// SubscriptionId is Azure subscription
+	SubscriptionId string
+	// ResourceGroup is resource group of VMs
+	ResourceGroup string
+}
+
+const (
+	azureNICsUrl = azure.ManagementURL + "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces"
+
+	azurePowerStateRunning = "PowerState/running"
+	azurePowerStateStopped = "PowerState/stopped"
+
+	// azurePowerWait is the interval between VM power state checks
+	azurePowerWait = time.Second * 10
+)
+
+type azureController struct {
+	AzureConfig
+	client *azure.Client
+}
+
+// NewAzure returns Controller managing Azure VMs of the resource group
+func NewAzure(cfg AzureConfig) (Controller, error) {
+	if cfg.SubscriptionId == "" || cfg.ResourceGroup == "" {
+		return nil, trace.BadParameter("subscription=%q, group=%q", cfg.SubscriptionId, cfg.ResourceGroup)
+	}
+	return &azureController{AzureConfig: cfg, client: azure.NewClient(cfg.AzureAuthParam)}, nil
+}
+
+// azureNIC is a subset of network interface resource
+type azureNIC struct {
+	Properties struct {
+		VirtualMachine struct {
+			ID string `json:"id"`
+		} `json:"virtualMachine"`
+		IPConfigurations []struct {
+			Properties struct {
+				PrivateIPAddress string `json:"privateIPAddress"`
+				PublicIPAddress  *struct {
+					ID string `json:"id"`
+				} `json:"publicIPAddress"`
+			} `json:"properties"`
+		} `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+// azureInstanceView is a subset of VM instance view
+type azureInstanceView struct {
+	Statuses []struct {
+		Code string `json:"code"`
+	} `json:"statuses"`
+}
+
+// Stop powers off the VM skipping graceful shutdown, VM resources stay allocated
+func (r *azureController) Stop(ctx context.Context, privateAddr string) error {
+	vmID, _, err := r.lookup(ctx, privateAddr)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = r.client.Do(ctx, "POST", fmt.Sprintf("%s%s/powerOff?skipShutdown=true&%s", azure.ManagementURL, vmID, azure.ComputeAPI), nil, nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(r.waitPowerState(ctx, vmID, azurePowerStateStopped))
+}
+
+// Start powers on the VM
+func (r *azureController) Start(ctx context.Context, privateAddr string) (string, error) {
+	vmID, publicIPID, err := r.lookup(ctx, privateAddr)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	err = r.client.Do(ctx, "POST", fmt.Sprintf("%s%s/start?%s", azure.ManagementURL, vmID, azure.ComputeAPI), nil, nil)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	if err = r.waitPowerState(ctx, vmID, azurePowerStateRunning); err != nil {
+		return "", trace.Wrap(err)
+	}
+
+	if publicIPID == "" {
+		return "", trace.NotFound("no public address for %v", privateAddr)
+	}
+	var publicIP struct {
+		Properties struct {
+			IPAddress string `json:"ipAddress"`
+		} `json:"properties"`
+	}
+	err = r.client.Do(ctx, "GET", fmt.Sprintf("%s%s?%s", azure.ManagementURL, publicIPID, azure.NetworkAPI), nil, &publicIP)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return publicIP.Properties.IPAddress, nil
+}
+
+// lookup finds VM and its public IP resources by private address
+func (r *azureController) lookup(ctx context.Context, privateAddr string) (vmID, publicIPID string, err error) {
+	var nics struct {
+		Value []azureNIC `json:"value"`
+	}
+	err = r.client.Do(ctx, "GET", fmt.Sprintf(azureNICsUrl+"?%s", r.SubscriptionId, r.ResourceGroup, azure.NetworkAPI), nil, &nics)
+	if err != nil {
+		return "", "", trace.Wrap(err)
+	}
+
+	for _, nic := range nics.Value {
+		for _, config := range nic.Properties.IPConfigurations {
+			if config.Properties.PrivateIPAddress != privateAddr || nic.Properties.VirtualMachine.ID == "" {
+				continue
+			}
+			if config.Properties.PublicIPAddress != nil {
+				publicIPID = config.Properties.PublicIPAddress.ID
+			}
+			return nic.Properties.VirtualMachine.ID, publicIPID, nil
+		}
+	}
+	return "", "", trace.NotFound("no VM with private address %v in %v", privateAddr, r.ResourceGroup)
+}
+
+// waitPowerState waits until VM reaches power state
+func (r *azureController) waitPowerState(ctx context.Context, vmID, state string) error {
+	retry := wait.Retryer{Delay: azurePowerWait, Attempts: 60}
+	return trace.Wrap(retry.Do(ctx, func() error {
+		var view azureInstanceView
+		err := r.client.Do(ctx, "GET", fmt.Sprintf("%s%s/instanceView?%s", azure.ManagementURL, vmID, azure.ComputeAPI), nil, &view)
+		if err != nil {
+			return wait.Continue(err.Error())
+		}
+		for _, status := range view.Statuses {
+			if strings.EqualFold(status.Code, state) {
+				return nil
+			}
+		}
+		return wait.Continue(fmt.Sprintf("%v is not in %v", vmID, state))
+	}))
+}
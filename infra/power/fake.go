@@ -0,0 +1,63 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gravitational/trace"
+)
+
+// Fake is an in-memory Controller for unit tests and local runs without cloud API.
+// Instances are not actually stopped
+type Fake struct {
+	sync.Mutex
+	stopped  map[string]bool
+	starts   int
+	reassign bool
+}
+
+// NewFake returns fake controller with all instances running,
+// every start assigns instance a new public address
+func NewFake() *Fake {
+	return &Fake{stopped: map[string]bool{}, reassign: true}
+}
+
+// NewLocal returns fake controller with all instances running,
+// which keep their public addresses across power cycles
+func NewLocal() *Fake {
+	return &Fake{stopped: map[string]bool{}}
+}
+
+// Stop marks instance as stopped
+func (f *Fake) Stop(ctx context.Context, privateAddr string) error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.stopped[privateAddr] = true
+	return nil
+}
+
+// Start marks instance as running and returns its new public address, if reassigned
+func (f *Fake) Start(ctx context.Context, privateAddr string) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.stopped[privateAddr] {
+		return "", trace.CompareFailed("instance %v is not stopped", privateAddr)
+	}
+	delete(f.stopped, privateAddr)
+	f.starts++
+	if !f.reassign {
+		return "", nil
+	}
+	return fmt.Sprintf("192.168.1.%d", f.starts), nil
+}
+
+// Stopped returns whether instance is stopped
+func (f *Fake) Stopped(privateAddr string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.stopped[privateAddr]
+}
@@ -0,0 +1,96 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gravitational/robotest/infra"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ssh"
+)
+
+// Controller manages power state of cloud VM instances identified by their private address,
+// as public address may change across power cycles
+type Controller interface {
+	// Stop forcibly powers off the instance and waits until it is stopped
+	Stop(ctx context.Context, privateAddr string) error
+	// Start powers on the instance, waits until it is running and returns its public address,
+	// empty address means it has not changed
+	Start(ctx context.Context, privateAddr string) (publicAddr string, err error)
+}
+
+// ConnectFn returns node accessible via public address
+type ConnectFn func(publicAddr string) infra.Node
+
+// New returns node which power state is managed by controller.
+// connect is used to access node after it is powered on
+func New(node infra.Node, ctrl Controller, connect ConnectFn) infra.PowerNode {
+	return &powerNode{node: node, ctrl: ctrl, connect: connect}
+}
+
+type powerNode struct {
+	sync.Mutex
+	node    infra.Node
+	ctrl    Controller
+	connect ConnectFn
+}
+
+// Addr returns current public address of the node
+func (r *powerNode) Addr() string {
+	return r.current().Addr()
+}
+
+// PrivateAddr returns private address of the node
+func (r *powerNode) PrivateAddr() string {
+	return r.current().PrivateAddr()
+}
+
+// Connect returns new SSH session to the node
+func (r *powerNode) Connect() (*ssh.Session, error) {
+	return r.current().Connect()
+}
+
+// Client returns new SSH client to the node
+func (r *powerNode) Client() (*ssh.Client, error) {
+	return r.current().Client()
+}
+
+// PowerOff forcibly powers off the node
+func (r *powerNode) PowerOff(ctx context.Context) error {
+	return trace.Wrap(r.ctrl.Stop(ctx, r.PrivateAddr()))
+}
+
+// PowerOn powers on the node, which may get new public address
+func (r *powerNode) PowerOn(ctx context.Context) error {
+	publicAddr, err := r.ctrl.Start(ctx, r.PrivateAddr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if publicAddr != "" && publicAddr != r.node.Addr() {
+		r.node = r.connect(publicAddr)
+	}
+	return nil
+}
+
+// HardReset forcibly powers the node off and on again
+func (r *powerNode) HardReset(ctx context.Context) error {
+	if err := r.PowerOff(ctx); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(r.PowerOn(ctx))
+}
+
+func (r *powerNode) String() string {
+	return fmt.Sprintf("%v", r.current())
+}
+
+func (r *powerNode) current() infra.Node {
+	r.Lock()
+	defer r.Unlock()
+	return r.node
+}
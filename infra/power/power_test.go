@@ -0,0 +1,50 @@
+package power
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gravitational/robotest/infra"
+	"github.com/gravitational/robotest/infra/ops"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerCycle(t *testing.T) {
+	ctx := context.Background()
+	ctrl := NewFake()
+	connect := func(publicAddr string) infra.Node {
+		return ops.New(publicAddr, "10.0.0.1", "user", "key")
+	}
+
+	node := New(connect("192.168.0.1"), ctrl, connect)
+
+	require.NoError(t, node.PowerOff(ctx))
+	assert.True(t, ctrl.Stopped("10.0.0.1"))
+
+	require.NoError(t, node.PowerOn(ctx))
+	assert.False(t, ctrl.Stopped("10.0.0.1"))
+	assert.Equal(t, "192.168.1.1", node.Addr(), "public address changes after power on")
+	assert.Equal(t, "10.0.0.1", node.PrivateAddr())
+
+	assert.Error(t, node.PowerOn(ctx), "node is already running")
+
+	require.NoError(t, node.HardReset(ctx))
+	assert.False(t, ctrl.Stopped("10.0.0.1"))
+	assert.Equal(t, "192.168.1.2", node.Addr())
+}
+
+func TestLocalPowerCycle(t *testing.T) {
+	ctx := context.Background()
+	ctrl := NewLocal()
+	connect := func(publicAddr string) infra.Node {
+		return ops.New(publicAddr, "10.0.0.1", "user", "key")
+	}
+
+	node := New(connect("192.168.0.1"), ctrl, connect)
+
+	require.NoError(t, node.HardReset(ctx))
+	assert.False(t, ctrl.Stopped("10.0.0.1"))
+	assert.Equal(t, "192.168.0.1", node.Addr(), "public address is kept")
+}
@@ -127,6 +127,12 @@ func (r *terraform) terraform(ctx context.Context) (err error) {
 		r.loadbalancerIP = match[1]
 	}
 
+	// parse auto scaling group / VM scale set name
+	match = reScalingGroup.FindStringSubmatch(output)
+	if len(match) == 2 {
+		r.scalingGroup = match[1]
+	}
+
 	// find installer IP
 	match = reInstallerIP.FindStringSubmatch(output)
 	if len(match) == 2 {
@@ -275,6 +281,7 @@ func (r *terraform) State() infra.ProvisionerState {
 		Nodes:            nodes,
 		Allocated:        allocated,
 		LoadBalancerAddr: r.loadbalancerIP,
+		ScalingGroup:     r.scalingGroup,
 	}
 }
 
@@ -364,6 +371,7 @@ type terraform struct {
 	stateDir       string
 	installerIP    string
 	loadbalancerIP string
+	scalingGroup   string
 }
 
 var (
@@ -371,4 +379,5 @@ var (
 	rePublicIPs    = regexp.MustCompile("(?m:^ *public_ips *= *([0-9\\. ]+))")
 	reLoadBalancer = regexp.MustCompile("(?m:^ *load_balancer *= *([a-zA-Z0-9][a-zA-Z0-9\\-\\.].*))")
 	reInstallerIP  = regexp.MustCompile("(?m:^ *installer_ip *= *([0-9\\. ]+))")
+	reScalingGroup = regexp.MustCompile("(?m:^ *scaling_group *= *([a-zA-Z0-9][a-zA-Z0-9\\-_\\.]*))")
 )
@@ -20,17 +20,21 @@ type OutputParseFn func(r *bufio.Reader) error
 const (
 	exitStatusUndefined = -1
 	exitCode            = "exit"
+	// outputTailLines is how many last lines of output are included into error on non-zero exit status
+	outputTailLines = 10
 )
 
 // Run is a simple method to run external program and don't care about its output or exit status
+// last lines of output are included into error on non-zero exit status
 func Run(ctx context.Context, client *ssh.Client, log logrus.FieldLogger, cmd string, env map[string]string) error {
-	exit, err := RunAndParse(ctx, client, log, cmd, env, ParseDiscard)
+	var tail []string
+	exit, err := RunAndParse(ctx, client, log, cmd, env, ParseTail(outputTailLines, &tail))
 	if err != nil {
 		return trace.Wrap(err, cmd)
 	}
 
 	if exit != 0 {
-		return trace.Errorf("%s returned %d", cmd, exit)
+		return trace.Errorf("%s returned %d: %s", cmd, exit, strings.Join(tail, "\n"))
 	}
 
 	return nil
@@ -147,6 +151,27 @@ func ParseDiscard(r *bufio.Reader) error {
 	return nil
 }
 
+// ParseTail keeps up to n last non-empty lines of output
+func ParseTail(n int, tail *[]string) OutputParseFn {
+	return func(r *bufio.Reader) error {
+		for {
+			line, err := r.ReadString('\n')
+			if line = strings.TrimSpace(line); line != "" {
+				*tail = append(*tail, line)
+				if len(*tail) > n {
+					*tail = (*tail)[1:]
+				}
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return trace.ConvertSystemError(err)
+			}
+		}
+	}
+}
+
 func ParseAsString(out *string) OutputParseFn {
 	return func(r *bufio.Reader) error {
 		b, err := ioutil.ReadAll(r)
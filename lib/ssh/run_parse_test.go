@@ -0,0 +1,17 @@
+package sshutils
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTail(t *testing.T) {
+	var tail []string
+	err := ParseTail(2, &tail)(bufio.NewReader(strings.NewReader("one\r\n\ntwo\nthree\n\n")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "three"}, tail)
+}
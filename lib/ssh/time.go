@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"math"
 	"strconv"
+	"time"
 
 	"github.com/gravitational/robotest/lib/utils"
 	"github.com/gravitational/robotest/lib/wait"
@@ -73,6 +74,39 @@ func checkTimeInSync(ctx context.Context, nodes []SshNode) func() error {
 	}
 }
 
+// timeSyncServices are services keeping system clock in sync on supported distributions
+const timeSyncServices = "chronyd chrony ntpd ntp systemd-timesyncd"
+
+// StopTimeSync stops time synchronization services on the node, and sets system clock to t
+func StopTimeSync(ctx context.Context, node SshNode, t time.Time) error {
+	cmd := fmt.Sprintf("for s in %s; do sudo systemctl stop $s 2>/dev/null; done; sudo date -u -s @%.3f",
+		timeSyncServices, float64(t.UnixNano())/1e9)
+	return trace.Wrap(Run(ctx, node.Client, node.Log, cmd, nil))
+}
+
+// RestoreTimeSync sets node system clock to the local one and starts time synchronization services.
+// Clock is set explicitly as sync services would refuse to step large offsets
+func RestoreTimeSync(ctx context.Context, node SshNode) error {
+	cmd := fmt.Sprintf("sudo date -u -s @%.3f; for s in %s; do sudo systemctl start $s 2>/dev/null; done; true",
+		float64(time.Now().UnixNano())/1e9, timeSyncServices)
+	return trace.Wrap(Run(ctx, node.Client, node.Log, cmd, nil))
+}
+
+// FixTimeSync sets system clocks of all nodes to the local one and checks they are in sync
+func FixTimeSync(ctx context.Context, nodes []SshNode) error {
+	errCh := make(chan error, len(nodes))
+	for _, node := range nodes {
+		go func(node SshNode) {
+			errCh <- RestoreTimeSync(ctx, node)
+		}(node)
+	}
+	if err := utils.CollectErrors(ctx, errCh); err != nil {
+		return trace.Wrap(err)
+	}
+
+	return trace.Wrap(CheckTimeSync(ctx, nodes))
+}
+
 const (
 	maxDelta = 200.0
 )
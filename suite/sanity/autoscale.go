@@ -1,15 +1,38 @@
 package sanity
 
 import (
+	"fmt"
+
 	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
 )
 
-// autoscale installs an initial cluster and then expands or gracefully shrinks it to given number of nodes
+type autoscaleParam struct {
+	installParam
+	// ScaleUp is how many worker nodes scaling group should have after scaling up
+	ScaleUp uint `json:"scale_up" validate:"required,gte=1"`
+	// ScaleDown is how many worker nodes scaling group should have after scaling down
+	ScaleDown uint `json:"scale_down" validate:"ltfield=ScaleUp"`
+}
+
+func (p autoscaleParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("scale_up=%v scale_down=%v", p.ScaleUp, p.ScaleDown)
+	return row, "", nil
+}
+
+// autoscale installs an initial cluster and then scales its worker group up and down to given number of nodes
 func autoscale(p interface{}) (gravity.TestFunc, error) {
-	param := p.(installParam)
+	param := p.(autoscaleParam)
 
 	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
-		masters, destroyFn, err := provisionNodes(g, cfg, param)
+		masters, destroyFn, err := provisionNodes(g, cfg, param.installParam)
 		g.OK("VMs ready", err)
 		defer destroyFn()
 
@@ -17,14 +40,14 @@ func autoscale(p interface{}) (gravity.TestFunc, error) {
 		g.OK("time sync", g.CheckTimeSync(masters))
 
 		// Scale Up
-		workers, err := g.AutoScale(3)
-		g.OK("asg-up", err)
+		workers, err := g.AutoScale(int(param.ScaleUp))
+		g.OK(fmt.Sprintf("asg-up to %d", param.ScaleUp), err)
 		g.OK("status-masters", g.Status(masters))
 		g.OK("status-workers", g.Status(workers))
 
 		// Scale Down
-		workers, err = g.AutoScale(1)
-		g.OK("asg-down", err)
+		workers, err = g.AutoScale(int(param.ScaleDown))
+		g.OK(fmt.Sprintf("asg-down to %d", param.ScaleDown), err)
 		g.OK("status-masters", g.Status(masters))
 		g.OK("status-workers", g.Status(workers))
 	}, nil
@@ -0,0 +1,35 @@
+package sanity
+
+import (
+	"github.com/gravitational/robotest/infra/gravity"
+)
+
+// backupRestore installs a cluster and creates application backup, which is collected into state dir
+// and has to contain files written by application backup hooks.
+// Sample workload, if requested, is not covered by backup hooks: it is deleted along with its volume data
+// before restore, so that restore runs against a cluster whose state has changed since backup.
+// Application managed state is then verified by taking another backup which has to contain the same files
+func backupRestore(p interface{}) (gravity.TestFunc, error) {
+	param := p.(installParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param)
+		g.OK("VMs ready", err)
+		defer destroyFn()
+
+		g.OK("installer downloaded", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+		workload := deployWorkload(g, nodes, nodes[0], param)
+
+		backup, err := g.Backup(nodes[0])
+		g.OK("backup", err)
+
+		if workload != nil {
+			g.OK("delete workload", g.DeleteWorkload(nodes, workload))
+		}
+		g.OK("restore", g.Restore(nodes[0], backup))
+		g.OK("status", g.Status(nodes))
+		g.OK("application state restored", g.VerifyRestore(nodes[0], backup))
+	}, nil
+}
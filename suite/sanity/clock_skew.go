@@ -0,0 +1,60 @@
+package sanity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+type clockSkewParam struct {
+	installParam
+	// Skew is clock offset to apply to one of the nodes, i.e. "5m" or "-1h"
+	Skew string `json:"skew" validate:"required"`
+	// ExpectStatus is cluster status expected while node clock is skewed
+	ExpectStatus string `json:"expect_status" validate:"required,eq=active|eq=degraded"`
+}
+
+func (p clockSkewParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("skew=%v", p.Skew)
+	return row, "", nil
+}
+
+// clockSkew installs a cluster, skews clock on one of the nodes,
+// verifies cluster status and etcd health, then restores time synchronization
+func clockSkew(p interface{}) (gravity.TestFunc, error) {
+	param := p.(clockSkewParam)
+
+	skew, err := time.ParseDuration(param.Skew)
+	if err != nil {
+		return nil, trace.Wrap(err, "invalid skew %q", param.Skew)
+	}
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("VMs ready", err)
+		defer destroyFn()
+
+		g.OK("installer downloaded", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.WaitStatus(nodes, gravity.StatusActive))
+
+		node := nodes[len(nodes)-1]
+		g.OK(fmt.Sprintf("skew clock on %v by %v", node, skew), g.SkewClock(node, skew))
+		g.OK(fmt.Sprintf("status %v under skew", param.ExpectStatus), g.WaitStatus(nodes, param.ExpectStatus))
+		g.OK("etcd healthy under skew", g.EtcdHealth(nodes[0]))
+
+		g.OK("restore clocks", g.RestoreClocks(nodes))
+		g.OK("time sync", g.CheckTimeSync(nodes))
+		g.OK("status after restore", g.WaitStatus(nodes, gravity.StatusActive))
+		g.OK("etcd healthy after restore", g.EtcdHealth(nodes[0]))
+	}, nil
+}
@@ -0,0 +1,55 @@
+package sanity
+
+import (
+	"fmt"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/sirupsen/logrus"
+)
+
+type componentKillParam struct {
+	installParam
+	// Component is Planet service to kill
+	Component gravity.Component `json:"component" validate:"required,eq=etcd|eq=kube-apiserver|eq=kubelet|eq=docker|eq=planet"`
+	// KillNodeType is node role to kill component on, see nodeXXX constants
+	KillNodeType string `json:"kill" validate:"required,eq=apimaster|eq=clmaster|eq=clbackup|eq=worker"`
+}
+
+func (p componentKillParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("component=%v kill=%v", p.Component, p.KillNodeType)
+	return row, "", nil
+}
+
+// componentKill installs a cluster, kills a component on the node with given role
+// and measures how long it takes to recover
+func componentKill(p interface{}) (gravity.TestFunc, error) {
+	param := p.(componentKillParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("VMs ready", err)
+		defer destroyFn()
+
+		g.OK("installer downloaded", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+
+		killed := []gravity.Gravity{nodeWithRole(g, nodes, param.KillNodeType)}
+		g.OK(fmt.Sprintf("kill %v on %v", param.Component, param.KillNodeType),
+			g.KillComponent(killed, param.Component))
+
+		elapsed, err := g.WaitRecovery(nodes, killed, param.Component)
+		g.OK(fmt.Sprintf("%v recovery", param.Component), err)
+		g.Logger().WithFields(logrus.Fields{
+			"component": param.Component, "kill": param.KillNodeType, "elapsed": elapsed.String(),
+		}).Info("time to recover")
+	}, nil
+}
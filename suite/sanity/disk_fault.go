@@ -0,0 +1,116 @@
+package sanity
+
+import (
+	"fmt"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+const (
+	// diskFill fills filesystem up to given percent
+	diskFill = "fill"
+	// diskReadOnly remounts filesystem read-only
+	diskReadOnly = "readonly"
+	// diskThrottle limits block device throughput
+	diskThrottle = "throttle"
+)
+
+type diskFaultParam struct {
+	installParam
+	// Fault is one of diskXXX constants
+	Fault string `json:"fault" validate:"required,eq=fill|eq=readonly|eq=throttle"`
+	// Path is filesystem path to fill or remount, state dir if empty
+	Path string `json:"path"`
+	// Percent is how full filesystem should become
+	Percent uint `json:"percent" validate:"lte=100"`
+	// Device is block device to throttle, disk holding Path if empty.
+	// Path should reside on Device, as throttling is verified by writing to Path
+	Device string `json:"device"`
+	// BytesPerSec is throughput limit for throttled device
+	BytesPerSec uint64 `json:"bps"`
+}
+
+func (p diskFaultParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("fault=%v", p.Fault)
+	return row, "", nil
+}
+
+// diskFault installs a cluster and injects disk fault on one of the nodes.
+// Filled up or read-only filesystem should make the cluster degraded until fault is removed.
+// Throttling does not trip cluster health checks, so write throughput is verified to drop
+// to the limit instead, and the time it takes to recover after removal is recorded
+func diskFault(p interface{}) (gravity.TestFunc, error) {
+	param := p.(diskFaultParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("VMs ready", err)
+		defer destroyFn()
+
+		g.OK("installer downloaded", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.WaitStatus(nodes, gravity.StatusActive))
+
+		path := param.Path
+		if path == "" {
+			path = param.StateDir
+		}
+
+		node := nodes[len(nodes)-1]
+		if param.Fault == diskThrottle {
+			diskThrottleFault(g, nodes, node, path, param)
+			return
+		}
+
+		var restore gravity.RestoreFn
+		switch param.Fault {
+		case diskFill:
+			restore, err = node.FillDisk(g.Context(), path, param.Percent)
+		case diskReadOnly:
+			restore, err = node.RemountReadOnly(g.Context(), path)
+		}
+		g.OK(fmt.Sprintf("inject %v on %v", param.Fault, node), err)
+		restore = restore.Once()
+		g.OnTeardown(fmt.Sprintf("restore %v on %v", param.Fault, node), restore)
+
+		g.OK("degraded", g.WaitStatus(nodes, gravity.StatusDegraded))
+		g.OK(fmt.Sprintf("restore %v", param.Fault), restore(g.Context()))
+		g.OK("recovered", g.WaitStatus(nodes, gravity.StatusActive))
+	}, nil
+}
+
+// diskThrottleFault throttles disk holding path on the node and verifies write throughput
+// drops to the limit, then removes the limit and records how long throughput takes to recover
+func diskThrottleFault(g *gravity.TestContext, nodes []gravity.Gravity, node gravity.Gravity, path string, param diskFaultParam) {
+	baseline, err := gravity.WriteSpeed(g.Context(), node, path)
+	g.OK(fmt.Sprintf("write speed on %v", node), err)
+	g.Require("baseline write speed above throttle limit", baseline > 2*param.BytesPerSec,
+		baseline, param.BytesPerSec)
+
+	device := param.Device
+	if device == "" {
+		device = path
+	}
+	restore, err := node.ThrottleDevice(g.Context(), device, param.BytesPerSec)
+	g.OK(fmt.Sprintf("inject %v on %v", param.Fault, node), err)
+	restore = restore.Once()
+	g.OnTeardown(fmt.Sprintf("restore %v on %v", param.Fault, node), restore)
+
+	throttled, err := gravity.WriteSpeed(g.Context(), node, path)
+	g.OK(fmt.Sprintf("throttled write speed on %v", node), err)
+	g.Require("write speed is limited", throttled <= 2*param.BytesPerSec, throttled, param.BytesPerSec)
+
+	g.OK(fmt.Sprintf("restore %v", param.Fault), restore(g.Context()))
+	elapsed, err := g.WaitWriteSpeed(node, path, baseline/2)
+	g.OK("write speed recovered", err)
+	g.RecordTiming("disk throughput recovery", elapsed)
+	g.OK("status", g.WaitStatus(nodes, gravity.StatusActive))
+}
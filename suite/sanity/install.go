@@ -1,6 +1,9 @@
 package sanity
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gravitational/robotest/infra/gravity"
 
 	"cloud.google.com/go/bigquery"
@@ -12,6 +15,15 @@ type installParam struct {
 	NodeCount uint `json:"nodes" validate:"gte=1"`
 	// Script if not empty would be executed with args provided after installer has been transferred
 	Script *scriptParam `json:"script"`
+	// Uninstall is whether to uninstall the cluster at the end and verify hosts are clean
+	Uninstall bool `json:"uninstall"`
+	// Workload if not empty would deploy sample stateful application after install
+	// and verify its data is intact after each cluster operation
+	Workload *gravity.WorkloadParam `json:"workload"`
+	// Probe if not empty is interval to probe cluster availability at during operations, i.e. "1s"
+	Probe string `json:"probe"`
+	// AirGap is whether to block egress from nodes except between themselves after installer is transferred
+	AirGap bool `json:"air_gap"`
 }
 
 type scriptParam struct {
@@ -35,6 +47,54 @@ func provisionNodes(g *gravity.TestContext, cfg gravity.ProvisionerConfig, param
 		WithNodes(param.NodeCount))
 }
 
+// airGap blocks egress from nodes except between themselves, if requested
+func airGap(g *gravity.TestContext, nodes []gravity.Gravity, param installParam) {
+	if !param.AirGap {
+		return
+	}
+	g.OK("air gap", g.AirGap(nodes))
+}
+
+// deployWorkload deploys sample stateful application if requested, returns nil otherwise.
+// node holds application volume unless storage class is given, and should not be removed from the cluster
+func deployWorkload(g *gravity.TestContext, nodes []gravity.Gravity, node gravity.Gravity, param installParam) *gravity.Workload {
+	if param.Workload == nil {
+		return nil
+	}
+	workload, err := g.DeployWorkload(nodes, node, *param.Workload)
+	g.OK("deploy workload", err)
+	return workload
+}
+
+// verifyWorkload verifies sample stateful application survived operation, if it was deployed
+func verifyWorkload(g *gravity.TestContext, nodes []gravity.Gravity, workload *gravity.Workload, operation string) {
+	if workload == nil {
+		return
+	}
+	g.OK(fmt.Sprintf("workload after %v", operation), g.VerifyWorkload(nodes, workload))
+}
+
+// startProbe starts availability probe from node if requested, returns nil otherwise
+func startProbe(g *gravity.TestContext, from gravity.Gravity, nodes []gravity.Gravity, workload *gravity.Workload, param installParam) *gravity.Probe {
+	if param.Probe == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(param.Probe)
+	g.OK(fmt.Sprintf("probe interval %q", param.Probe), err)
+
+	probe, err := g.StartProbe(from, nodes, workload, interval)
+	g.OK("start availability probe", err)
+	return probe
+}
+
+// probeCheckpoint reports cluster availability during operation, if probe was started
+func probeCheckpoint(probe *gravity.Probe, operation string) {
+	if probe == nil {
+		return
+	}
+	probe.Checkpoint(operation)
+}
+
 func install(p interface{}) (gravity.TestFunc, error) {
 	param := p.(installParam)
 
@@ -48,10 +108,19 @@ func install(p interface{}) (gravity.TestFunc, error) {
 			g.OK("post bootstrap script",
 				g.ExecScript(nodes, param.Script.Url, param.Script.Args))
 		}
+		airGap(g, nodes, param)
 
 		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
 
 		g.OK("status", g.Status(nodes))
+		g.OK("network CIDRs", g.CheckNetworkCIDRs(nodes, param.InstallParam))
+		workload := deployWorkload(g, nodes, nodes[0], param)
+		verifyWorkload(g, nodes, workload, "install")
+
+		if param.Uninstall {
+			g.OK("uninstall", g.Uninstall(nodes))
+			g.OK("hosts clean", g.CheckClean(nodes, param.StateDir))
+		}
 	}, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/robotest/lib/constants"
 	"github.com/gravitational/trace"
 
 	"github.com/sirupsen/logrus"
@@ -72,7 +73,13 @@ func lossAndRecovery(p interface{}) (gravity.TestFunc, error) {
 	param := p.(lossAndRecoveryParam)
 
 	return func(g *gravity.TestContext, baseConfig gravity.ProvisionerConfig) {
-		config := baseConfig.WithNodes(param.NodeCount + 1)
+		// Ops Center provisions replacement nodes on demand, otherwise a spare node is allocated upfront
+		opsProvisioned := baseConfig.CloudProvider == constants.Ops
+		spareNodes := uint(1)
+		if opsProvisioned {
+			spareNodes = 0
+		}
+		config := baseConfig.WithNodes(param.NodeCount + spareNodes)
 
 		allNodes, destroyFn, err := g.Provision(config)
 		g.OK("provision nodes", err)
@@ -81,39 +88,56 @@ func lossAndRecovery(p interface{}) (gravity.TestFunc, error) {
 		g.OK("download installer", g.SetInstaller(allNodes, config.InstallerURL, "install"))
 
 		nodes := allNodes[0:param.NodeCount]
+		var spare gravity.Gravity
+		if !opsProvisioned {
+			spare = allNodes[param.NodeCount]
+		}
 		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
 		g.OK("install status", g.Status(nodes))
 
-		nodes, removed, err := removeNode(g, nodes, param.ReplaceNodeType, param.PowerOff)
+		removed := nodeWithRole(g, nodes, param.ReplaceNodeType)
+		kept := excludeNode(nodes, removed)[0]
+		workload := deployWorkload(g, nodes, kept, param.installParam)
+		probe := startProbe(g, kept, nodes, workload, param.installParam)
+
+		nodes, err = removeNode(g, nodes, removed, param.PowerOff)
 		g.OK(fmt.Sprintf("node for removal=%v, poweroff=%v", removed, param.PowerOff), err)
 
 		now := time.Now()
 		g.OK("wait for cluster to be ready", g.Status(nodes))
 		g.Logger().WithFields(logrus.Fields{"nodes": nodes, "elapsed": fmt.Sprintf("%v", time.Since(now))}).
 			Info("cluster is available")
+		probeCheckpoint(probe, "node loss")
 
 		if param.ExpandBeforeShrink {
-			g.OK("expand before shrinking",
-				g.Expand(nodes, allNodes[param.NodeCount:param.NodeCount+1], param.InstallParam))
-			nodes = append(nodes, allNodes[param.NodeCount])
+			nodes, err = expandByOne(g, nodes, spare, param.InstallParam)
+			g.OK("expand before shrinking", err)
 
 			roles, err := g.NodesByRole(nodes)
 			g.OK("node roles after expand", err)
 			g.Logger().WithFields(logrus.Fields{"roles": roles, "nodes": nodes}).
 				Info("roles after expand")
 
+			probeCheckpoint(probe, "expand")
+			verifyWorkload(g, nodes, workload, "expand")
+
 			g.OK("remove old node", g.RemoveNode(nodes, removed))
+			probeCheckpoint(probe, "remove")
+			verifyWorkload(g, nodes, workload, "remove")
 		} else {
 			g.OK("remove lost node", g.RemoveNode(nodes, removed))
+			probeCheckpoint(probe, "remove")
+			verifyWorkload(g, nodes, workload, "remove")
 
 			roles, err := g.NodesByRole(nodes)
 			g.OK("node role after remove", err)
 			g.Logger().WithFields(logrus.Fields{"roles": roles, "nodes": nodes}).
 				Info("Roles after remove")
 
-			g.OK("replace node",
-				g.Expand(nodes, allNodes[param.NodeCount:param.NodeCount+1], param.InstallParam))
-			nodes = append(nodes, allNodes[param.NodeCount])
+			nodes, err = expandByOne(g, nodes, spare, param.InstallParam)
+			g.OK("replace node", err)
+			probeCheckpoint(probe, "expand")
+			verifyWorkload(g, nodes, workload, "expand")
 		}
 
 		roles, err := g.NodesByRole(nodes)
@@ -123,25 +147,57 @@ func lossAndRecovery(p interface{}) (gravity.TestFunc, error) {
 	}, nil
 }
 
+// expandByOne joins spare node to the cluster,
+// or requests a new node from Ops Center when spare is nil
+func expandByOne(g *gravity.TestContext, nodes []gravity.Gravity, spare gravity.Gravity, param gravity.InstallParam) ([]gravity.Gravity, error) {
+	if spare == nil {
+		added, err := g.ExpandOps(nodes, 1)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return append(nodes, added...), nil
+	}
+
+	err := g.Expand(nodes, []gravity.Gravity{spare}, param)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return append(nodes, spare), nil
+}
+
+// removeNode excludes node from the list, optionally powering it off
 func removeNode(g *gravity.TestContext,
 	nodes []gravity.Gravity,
-	nodeRoleType string, powerOff bool) (remaining []gravity.Gravity, removed gravity.Gravity, err error) {
+	removed gravity.Gravity, powerOff bool) (remaining []gravity.Gravity, err error) {
+
+	remaining = excludeNode(nodes, removed)
 
+	if powerOff {
+		ctx, cancel := context.WithTimeout(g.Context(), time.Minute)
+		defer cancel()
+		err = removed.PowerOff(ctx, gravity.Graceful(false))
+	}
+
+	return remaining, trace.Wrap(err)
+}
+
+// nodeWithRole picks a node playing given role in the cluster, see nodeXXX constants
+func nodeWithRole(g *gravity.TestContext, nodes []gravity.Gravity, nodeRoleType string) (node gravity.Gravity) {
 	roles, err := g.NodesByRole(nodes)
 	g.OK("node roles", err)
 	g.Logger().WithFields(logrus.Fields{"roles": roles, "nodes": nodes}).Info("Cluster Roles")
 
 	switch nodeRoleType {
 	case nodeApiMaster:
-		removed = roles.ApiMaster
+		node = roles.ApiMaster
 	case nodeClusterMaster:
 		if roles.ApiMaster == roles.ClusterMaster {
 			g.Logger().Warn("API and Cluster masters reside on same node, will try relocate")
 			g.OK("cluster master relocation", gravity.RelocateClusterMaster(g.Context(), roles.ApiMaster))
-			return removeNode(g, nodes, nodeRoleType, powerOff)
+			return nodeWithRole(g, nodes, nodeRoleType)
 		}
 		g.Require("gravity-site master != apiserver", roles.ApiMaster != roles.ClusterMaster)
-		removed = roles.ClusterMaster
+		node = roles.ClusterMaster
 	case nodeClusterBackup:
 		g.Require("2 cluster backup nodes", len(roles.ClusterBackup) == 2)
 		// avoid picking up ApiMaster, as it'll become a very different test then
@@ -149,24 +205,15 @@ func removeNode(g *gravity.TestContext,
 		if roles.ClusterBackup[idx] == roles.ApiMaster {
 			idx = 1
 		}
-		removed = roles.ClusterBackup[idx]
+		node = roles.ClusterBackup[idx]
 	case nodeRegularNode:
 		g.Require("worker nodes exist", len(roles.Regular) > 0)
-		removed = roles.Regular[rand.Intn(len(roles.Regular))]
+		node = roles.Regular[rand.Intn(len(roles.Regular))]
 	default:
 		g.Logger().WithField("role", nodeRoleType).Error("unexpected node role")
 		g.FailNow()
 	}
-
-	remaining = excludeNode(nodes, removed)
-
-	if powerOff {
-		ctx, cancel := context.WithTimeout(g.Context(), time.Minute)
-		defer cancel()
-		err = removed.PowerOff(ctx, gravity.Graceful(false))
-	}
-
-	return remaining, removed, trace.Wrap(err)
+	return node
 }
 
 func excludeNode(nodes []gravity.Gravity, excl gravity.Gravity) []gravity.Gravity {
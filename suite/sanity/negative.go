@@ -0,0 +1,125 @@
+package sanity
+
+import (
+	"fmt"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// join a node using wrong join token
+	negativeJoinToken = "join_token"
+	// join a node without enough disk space in state dir
+	negativeDisk = "disk"
+	// remove the only master node from the cluster
+	negativeRemoveMaster = "remove_master"
+)
+
+// negativeExpect are default substrings of expected failure per scenario, empty matches any failure
+var negativeExpect = map[string]string{
+	negativeJoinToken:    "",
+	negativeDisk:         "disk space",
+	negativeRemoveMaster: "",
+}
+
+type negativeParam struct {
+	installParam
+	// Scenario is operation expected to fail, see negativeXXX constants
+	Scenario string `json:"scenario" validate:"required,eq=join_token|eq=disk|eq=remove_master"`
+	// Expect is substring of the expected failure message, scenario default is used if empty
+	Expect string `json:"expect"`
+}
+
+func (p negativeParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("scenario=%v", p.Scenario)
+	return row, "", nil
+}
+
+// negativeVariety runs every negative scenario as a separate test,
+// removing the only master is skipped unless cluster has a single node
+func negativeVariety(p interface{}) (gravity.TestFunc, error) {
+	template := negativeParam{installParam: p.(installParam)}
+
+	return func(g *gravity.TestContext, baseConfig gravity.ProvisionerConfig) {
+		for _, scenario := range []string{negativeJoinToken, negativeDisk, negativeRemoveMaster} {
+			param := template
+			param.Scenario = scenario
+			if scenario == negativeRemoveMaster && param.NodeCount > 1 {
+				g.Logger().WithField("nodes", param.NodeCount).Info("skipping remove_master, cluster has more than one node")
+				continue
+			}
+			fun, err := negative(param)
+			if err != nil {
+				g.Logger().WithFields(logrus.Fields{
+					"param": param, "error": err,
+				}).Error("configuration error")
+				g.FailNow()
+			}
+			g.Run(fun, baseConfig.WithTag(scenario), logrus.Fields{"param": param})
+		}
+	}, nil
+}
+
+// negative installs a cluster, verifies operation expected to fail does fail
+// with the expected error, and that cluster stays available
+func negative(p interface{}) (gravity.TestFunc, error) {
+	param := p.(negativeParam)
+
+	expect := param.Expect
+	if expect == "" {
+		expect = negativeExpect[param.Scenario]
+	}
+	match := gravity.AnyError
+	if expect != "" {
+		match = gravity.ErrorContains(expect)
+	}
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		spareNodes := uint(1)
+		if param.Scenario == negativeRemoveMaster {
+			spareNodes = 0
+		}
+
+		allNodes, destroyFn, err := g.Provision(cfg.WithOS(param.OSFlavor).
+			WithStorageDriver(param.DockerStorageDriver).
+			WithNodes(param.NodeCount + spareNodes))
+		g.OK("provision nodes", err)
+		defer destroyFn()
+
+		g.OK("download installer", g.SetInstaller(allNodes, cfg.InstallerURL, "install"))
+
+		nodes := allNodes[0:param.NodeCount]
+		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+
+		switch param.Scenario {
+		case negativeJoinToken:
+			install := param.InstallParam
+			install.Token = "ROBOTEST-WRONG-TOKEN"
+			g.ExpectFailure("join with wrong token",
+				g.Expand(nodes, allNodes[param.NodeCount:], install), match)
+		case negativeDisk:
+			spare := allNodes[param.NodeCount]
+			restore, err := spare.FillDisk(g.Context(), param.StateDir, 99)
+			g.OK(fmt.Sprintf("fill %v on %v", param.StateDir, spare), err)
+			g.OnTeardown(fmt.Sprintf("remove fill on %v", spare), restore)
+
+			g.ExpectFailure("join without disk space",
+				g.Expand(nodes, []gravity.Gravity{spare}, param.InstallParam), match)
+		case negativeRemoveMaster:
+			g.Require("single master cluster", len(nodes) == 1)
+			g.ExpectFailure("remove the only master", g.RemoveNode(nodes, nodes[0]), match)
+		}
+
+		g.OK("status after failure", g.Status(nodes))
+	}, nil
+}
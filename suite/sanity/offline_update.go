@@ -0,0 +1,47 @@
+package sanity
+
+import (
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+type offlineUpdateParam struct {
+	installParam
+	// BaseInstallerURL is initial app installer URL
+	BaseInstallerURL string `json:"from" validate:"required"`
+}
+
+func (p offlineUpdateParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["upgrade_from"] = p.BaseInstallerURL
+	return row, "", nil
+}
+
+// offlineUpdate installs a cluster from base installer, then updates it
+// to the suite installer uploaded directly to the cluster, without Ops Center
+func offlineUpdate(p interface{}) (gravity.TestFunc, error) {
+	param := p.(offlineUpdateParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("provision nodes", err)
+		defer destroyFn()
+
+		g.OK("base installer", g.SetInstaller(nodes, param.BaseInstallerURL, "base"))
+		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+		workload := deployWorkload(g, nodes, nodes[0], param.installParam)
+		probe := startProbe(g, nodes[0], nodes, workload, param.installParam)
+
+		g.OK("offline update", g.OfflineUpdate(nodes, cfg.InstallerURL))
+		g.OK("status", g.Status(nodes))
+		probeCheckpoint(probe, "offline update")
+		verifyWorkload(g, nodes, workload, "offline update")
+	}, nil
+}
@@ -0,0 +1,114 @@
+package sanity
+
+import (
+	"fmt"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// unload and blacklist a kernel module required by Kubernetes
+	preflightKernelModule = "kernel_module"
+	// fill state dir filesystem so available disk space is below requirements
+	preflightDisk = "disk"
+	// occupy a port required by cluster components
+	preflightPort = "port"
+	// pass a docker device which does not exist
+	preflightDockerDevice = "docker_device"
+)
+
+const (
+	preflightModule          = "br_netfilter"
+	preflightOccupiedPort    = 2379
+	preflightMissingDevice   = "/dev/robotest-missing"
+	preflightDiskFillPercent = 99
+)
+
+// preflightExpect are default substrings of expected preflight failure per check
+var preflightExpect = map[string]string{
+	preflightKernelModule: preflightModule,
+	preflightDisk:         "disk space",
+	preflightPort:         fmt.Sprint(preflightOccupiedPort),
+	preflightDockerDevice: preflightMissingDevice,
+}
+
+type preflightParam struct {
+	installParam
+	// Check is host misconfiguration preflight checks should detect, see preflightXXX constants
+	Check string `json:"check" validate:"required,eq=kernel_module|eq=disk|eq=port|eq=docker_device"`
+	// Expect is substring of the expected failure message, check default is used if empty
+	Expect string `json:"expect"`
+}
+
+func (p preflightParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("check=%v", p.Check)
+	return row, "", nil
+}
+
+// preflightVariety runs every preflight check as a separate test
+func preflightVariety(p interface{}) (gravity.TestFunc, error) {
+	template := preflightParam{installParam: p.(installParam)}
+
+	return func(g *gravity.TestContext, baseConfig gravity.ProvisionerConfig) {
+		for _, check := range []string{preflightKernelModule, preflightDisk, preflightPort, preflightDockerDevice} {
+			param := template
+			param.Check = check
+			fun, err := preflight(param)
+			if err != nil {
+				g.Logger().WithFields(logrus.Fields{
+					"param": param, "error": err,
+				}).Error("configuration error")
+				g.FailNow()
+			}
+			g.Run(fun, baseConfig.WithTag(check), logrus.Fields{"param": param})
+		}
+	}, nil
+}
+
+// preflight misconfigures hosts before install and verifies install
+// is rejected by preflight checks with the expected error
+func preflight(p interface{}) (gravity.TestFunc, error) {
+	param := p.(preflightParam)
+
+	expect := param.Expect
+	if expect == "" {
+		expect = preflightExpect[param.Check]
+	}
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := g.Provision(cfg.WithOS(param.OSFlavor).
+			WithStorageDriver(param.DockerStorageDriver).
+			WithNodes(param.NodeCount))
+		g.OK("provision nodes", err)
+		defer destroyFn()
+
+		g.OK("download installer", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+
+		for _, node := range nodes {
+			var restore gravity.RestoreFn
+			switch param.Check {
+			case preflightKernelModule:
+				restore, err = gravity.UnloadKernelModules(g.Context(), node, preflightModule)
+			case preflightDisk:
+				restore, err = node.FillDisk(g.Context(), param.StateDir, preflightDiskFillPercent)
+			case preflightPort:
+				restore, err = gravity.OccupyPorts(g.Context(), node, preflightOccupiedPort)
+			case preflightDockerDevice:
+				restore, err = gravity.SetDockerDevice(node, preflightMissingDevice)
+			}
+			g.OK(fmt.Sprintf("misconfigure %v on %v", param.Check, node), err)
+			g.OnTeardown(fmt.Sprintf("restore %v on %v", param.Check, node), restore)
+		}
+
+		g.ExpectFailure("install", g.OfflineInstall(nodes, param.InstallParam), gravity.ErrorContains(expect))
+	}, nil
+}
@@ -0,0 +1,67 @@
+package sanity
+
+import (
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+type proxyParam struct {
+	installParam
+	// BaseInstallerURL if not empty is initial app installer URL, cluster is then upgraded to suite installer
+	BaseInstallerURL string `json:"from"`
+}
+
+func (p proxyParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["upgrade_from"] = p.BaseInstallerURL
+	return row, "", nil
+}
+
+// proxy runs forward proxy on an extra node, blocks direct egress from cluster nodes
+// and installs, and optionally upgrades, the cluster using proxy. Sample stateful application
+// is deployed to pull its image via proxy, then proxy log is checked for requests from cluster nodes
+func proxy(p interface{}) (gravity.TestFunc, error) {
+	param := p.(proxyParam)
+	if param.Workload == nil {
+		param.Workload = &gravity.WorkloadParam{}
+	}
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		allNodes, destroyFn, err := g.Provision(cfg.WithOS(param.OSFlavor).
+			WithStorageDriver(param.DockerStorageDriver).
+			WithNodes(param.NodeCount + 1))
+		g.OK("provision nodes", err)
+		defer destroyFn()
+
+		nodes := allNodes[0:param.NodeCount]
+		proxyNode := allNodes[param.NodeCount]
+
+		installerURL := cfg.InstallerURL
+		if param.BaseInstallerURL != "" {
+			installerURL = param.BaseInstallerURL
+		}
+		g.OK("download installer", g.SetInstaller(nodes, installerURL, "install"))
+
+		proxy, err := g.StartProxy(proxyNode, nodes)
+		g.OK("start proxy", err)
+		g.OK("use proxy", g.UseProxy(nodes, proxy))
+
+		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+		workload := deployWorkload(g, nodes, nodes[0], param.installParam)
+
+		if param.BaseInstallerURL != "" {
+			g.OK("upgrade", g.Upgrade(nodes, cfg.InstallerURL, "upgrade"))
+			g.OK("status after upgrade", g.Status(nodes))
+			verifyWorkload(g, nodes, workload, "upgrade")
+		}
+
+		g.OK("requests via proxy", g.CheckProxied(proxy, nodes))
+	}, nil
+}
@@ -0,0 +1,71 @@
+package sanity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+type rebootParam struct {
+	installParam
+	// Rolling is whether to reboot nodes one at a time, or all of them simultaneously
+	Rolling bool `json:"rolling"`
+	// Graceful is whether to shutdown nodes gracefully or force reboot
+	Graceful bool `json:"graceful"`
+}
+
+func (p rebootParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["extra"] = fmt.Sprintf("rolling=%v graceful=%v", p.Rolling, p.Graceful)
+	return row, "", nil
+}
+
+// reboot installs a cluster, then reboots its nodes either one by one or all at once,
+// measuring time for cluster to become healthy again
+func reboot(p interface{}) (gravity.TestFunc, error) {
+	param := p.(rebootParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("VMs ready", err)
+		defer destroyFn()
+
+		g.OK("installer downloaded", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		g.OK("application installed", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+
+		if !param.Rolling {
+			rebootAndWait(g, nodes, nodes, param.Graceful)
+			return
+		}
+
+		for i, node := range nodes {
+			// probe from the next node, as the one rebooting loses its connection
+			var probe *gravity.Probe
+			if len(nodes) > 1 {
+				probe = startProbe(g, nodes[(i+1)%len(nodes)], nodes, nil, param.installParam)
+			}
+			rebootAndWait(g, nodes, []gravity.Gravity{node}, param.Graceful)
+			if probe != nil {
+				probe.Checkpoint(fmt.Sprintf("reboot %v", node))
+				probe.Stop()
+			}
+		}
+	}, nil
+}
+
+// rebootAndWait reboots nodes and records time it takes cluster to become healthy
+func rebootAndWait(g *gravity.TestContext, cluster, nodes []gravity.Gravity, graceful bool) {
+	start := time.Now()
+	g.OK(fmt.Sprintf("reboot %v", nodes), g.Reboot(nodes, gravity.Graceful(graceful)))
+	g.OK("cluster healthy after reboot", g.CheckHealth(cluster))
+	g.RecordTiming(fmt.Sprintf("healthy after reboot of %v, graceful=%v", nodes, graceful), time.Since(start))
+}
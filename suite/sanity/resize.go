@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/robotest/lib/constants"
 	"github.com/gravitational/trace"
 
 	"cloud.google.com/go/bigquery"
@@ -30,21 +31,71 @@ func resize(p interface{}) (gravity.TestFunc, error) {
 	param := p.(resizeParam)
 
 	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		if cfg.CloudProvider == constants.Ops {
+			resizeOps(g, cfg, param)
+			return
+		}
+
+		total := param.ToNodes
+		if param.NodeCount > total {
+			total = param.NodeCount
+		}
 		nodes, destroyFn, err := g.Provision(cfg.WithOS(param.OSFlavor).
 			WithStorageDriver(param.DockerStorageDriver).
-			WithNodes(param.ToNodes))
+			WithNodes(total))
 		g.OK("provision nodes", err)
 		defer destroyFn()
 
 		g.OK("download installer", g.SetInstaller(nodes, cfg.InstallerURL, "install"))
+		airGap(g, nodes, param.installParam)
 		g.OK(fmt.Sprintf("install on %d node", param.NodeCount),
 			g.OfflineInstall(nodes[0:param.NodeCount], param.InstallParam))
 		g.OK("status", g.Status(nodes[0:param.NodeCount]))
 		g.OK("time sync", g.CheckTimeSync(nodes))
+		workload := deployWorkload(g, nodes[0:param.NodeCount], nodes[0], param.installParam)
+		probe := startProbe(g, nodes[0], nodes[0:param.NodeCount], workload, param.installParam)
+
+		if param.ToNodes < param.NodeCount {
+			g.OK(fmt.Sprintf("shrink to %d nodes", param.ToNodes),
+				g.ShrinkLeave(nodes[0:param.ToNodes], nodes[param.ToNodes:param.NodeCount]))
+			g.OK("status", g.Status(nodes[0:param.ToNodes]))
+			probeCheckpoint(probe, "shrink")
+			verifyWorkload(g, nodes[0:param.ToNodes], workload, "shrink")
+			return
+		}
 
 		g.OK(fmt.Sprintf("expand to %d nodes", param.ToNodes),
 			g.Expand(nodes[0:param.NodeCount], nodes[param.NodeCount:param.ToNodes],
 				param.InstallParam))
 		g.OK("status", g.Status(nodes[0:param.ToNodes]))
+		probeCheckpoint(probe, "expand")
+		verifyWorkload(g, nodes[0:param.ToNodes], workload, "expand")
 	}, nil
 }
+
+// resizeOps requests Ops Center to install an initial cluster and then expands or shrinks it
+func resizeOps(g *gravity.TestContext, cfg gravity.ProvisionerConfig, param resizeParam) {
+	nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+	g.OK("provision nodes", err)
+	defer destroyFn()
+
+	g.OK("status", g.Status(nodes))
+	workload := deployWorkload(g, nodes, nodes[0], param.installParam)
+
+	if param.ToNodes < param.NodeCount {
+		g.OK(fmt.Sprintf("shrink to %d nodes", param.ToNodes),
+			g.ShrinkOps(nodes[0:param.ToNodes], nodes[param.ToNodes:]))
+		nodes = nodes[0:param.ToNodes]
+		g.OK("status", g.Status(nodes))
+		verifyWorkload(g, nodes, workload, "shrink")
+		return
+	}
+
+	added, err := g.ExpandOps(nodes, int(param.ToNodes)-int(param.NodeCount))
+	g.OK(fmt.Sprintf("expand to %d nodes", param.ToNodes), err)
+
+	nodes = append(nodes, added...)
+	g.OK("status", g.Status(nodes))
+	g.OK("time sync", g.CheckTimeSync(nodes))
+	verifyWorkload(g, nodes, workload, "expand")
+}
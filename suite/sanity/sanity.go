@@ -24,7 +24,19 @@ func Suite() *config.Config {
 	cfg.Add("recover", lossAndRecovery, lossAndRecoveryParam{installParam: defaultInstallParam})
 	cfg.Add("recoverV", lossAndRecoveryVariety, defaultInstallParam)
 	cfg.Add("upgrade3lts", upgrade, upgradeParam{installParam: defaultInstallParam})
-	cfg.Add("autoscale", autoscale, defaultInstallParam)
+	cfg.Add("disk_fault", diskFault, diskFaultParam{installParam: defaultInstallParam, Percent: 95, BytesPerSec: 1 << 20})
+	cfg.Add("component_kill", componentKill, componentKillParam{installParam: defaultInstallParam})
+	cfg.Add("clock_skew", clockSkew, clockSkewParam{installParam: defaultInstallParam, Skew: "5m", ExpectStatus: gravity.StatusDegraded})
+	cfg.Add("reboot", reboot, rebootParam{installParam: defaultInstallParam})
+	cfg.Add("upgrade_rollback", upgradeRollback, upgradeRollbackParam{installParam: defaultInstallParam, FailPhase: "/masters"})
+	cfg.Add("offline_update", offlineUpdate, offlineUpdateParam{installParam: defaultInstallParam})
+	cfg.Add("backup_restore", backupRestore, defaultInstallParam)
+	cfg.Add("negative", negative, negativeParam{installParam: defaultInstallParam})
+	cfg.Add("negativeV", negativeVariety, defaultInstallParam)
+	cfg.Add("preflight", preflight, preflightParam{installParam: defaultInstallParam})
+	cfg.Add("preflightV", preflightVariety, defaultInstallParam)
+	cfg.Add("proxy", proxy, proxyParam{installParam: defaultInstallParam})
+	cfg.Add("autoscale", autoscale, autoscaleParam{installParam: defaultInstallParam, ScaleUp: 3, ScaleDown: 1})
 
 	return cfg
 }
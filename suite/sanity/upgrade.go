@@ -1,6 +1,11 @@
 package sanity
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/gravitational/robotest/infra/gravity"
 	"github.com/gravitational/trace"
 
@@ -9,8 +14,28 @@ import (
 
 type upgradeParam struct {
 	installParam
-	// BaseInstallerURL is initial app installer URL
-	BaseInstallerURL string `json:"from" validate:"required"`
+	// BaseInstallers are app installers to install initially and then upgrade through in order,
+	// before upgrading to the suite installer
+	BaseInstallers installerList `json:"from" validate:"required,dive,required"`
+}
+
+// installerList is a list of installer URLs, which could also be given as a single string
+type installerList []string
+
+// UnmarshalJSON accepts either a single installer URL or a list of them
+func (l *installerList) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*l = installerList{url}
+		return nil
+	}
+
+	var urls []string
+	if err := json.Unmarshal(data, &urls); err != nil {
+		return trace.BadParameter("expected installer URL or list of URLs, got %s", data)
+	}
+	*l = installerList(urls)
+	return nil
 }
 
 func (p upgradeParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
@@ -19,10 +44,12 @@ func (p upgradeParam) Save() (row map[string]bigquery.Value, insertID string, er
 		return nil, "", trace.Wrap(err)
 	}
 
-	row["upgrade_from"] = p.BaseInstallerURL
+	row["upgrade_from"] = strings.Join(p.BaseInstallers, ",")
 	return row, "", nil
 }
 
+// upgrade installs the first base installer, then upgrades the cluster through the rest of them
+// and finally to the suite installer, verifying cluster after each hop
 func upgrade(p interface{}) (gravity.TestFunc, error) {
 	param := p.(upgradeParam)
 
@@ -31,10 +58,23 @@ func upgrade(p interface{}) (gravity.TestFunc, error) {
 		g.OK("provision nodes", err)
 		defer destroyFn()
 
-		g.OK("base installer", g.SetInstaller(nodes, param.BaseInstallerURL, "base"))
+		g.OK("base installer", g.SetInstaller(nodes, param.BaseInstallers[0], "base"))
+		airGap(g, nodes, param.installParam)
 		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
 		g.OK("status", g.Status(nodes))
-		g.OK("upgrade", g.Upgrade(nodes, cfg.InstallerURL, "upgrade"))
-		g.OK("status", g.Status(nodes))
+		workload := deployWorkload(g, nodes, nodes[0], param.installParam)
+		probe := startProbe(g, nodes[0], nodes, workload, param.installParam)
+
+		hops := append(append([]string{}, param.BaseInstallers[1:]...), cfg.InstallerURL)
+		for i, installerURL := range hops {
+			hop := fmt.Sprintf("upgrade %d/%d", i+1, len(hops))
+			start := time.Now()
+			g.OK(fmt.Sprintf("%v to %v", hop, installerURL),
+				g.Upgrade(nodes, installerURL, fmt.Sprintf("upgrade-%d", i+1)))
+			g.OK(fmt.Sprintf("status after %v", hop), g.Status(nodes))
+			g.RecordTiming(hop, time.Since(start))
+			probeCheckpoint(probe, hop)
+			verifyWorkload(g, nodes, workload, hop)
+		}
 	}, nil
 }
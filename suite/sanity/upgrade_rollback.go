@@ -0,0 +1,51 @@
+package sanity
+
+import (
+	"fmt"
+
+	"github.com/gravitational/robotest/infra/gravity"
+	"github.com/gravitational/trace"
+
+	"cloud.google.com/go/bigquery"
+)
+
+type upgradeRollbackParam struct {
+	installParam
+	// BaseInstallerURL is initial app installer URL
+	BaseInstallerURL string `json:"from" validate:"required"`
+	// FailPhase is top level upgrade plan phase to interrupt, i.e. "/masters"
+	FailPhase string `json:"fail_phase" validate:"required"`
+}
+
+func (p upgradeRollbackParam) Save() (row map[string]bigquery.Value, insertID string, err error) {
+	row, _, err = p.installParam.Save()
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+
+	row["upgrade_from"] = p.BaseInstallerURL
+	row["extra"] = fmt.Sprintf("fail_phase=%v", p.FailPhase)
+	return row, "", nil
+}
+
+// upgradeRollback installs a cluster, steps through manual upgrade until the phase to fail,
+// rolls the upgrade back and verifies cluster is back to original version and healthy
+func upgradeRollback(p interface{}) (gravity.TestFunc, error) {
+	param := p.(upgradeRollbackParam)
+
+	return func(g *gravity.TestContext, cfg gravity.ProvisionerConfig) {
+		nodes, destroyFn, err := provisionNodes(g, cfg, param.installParam)
+		g.OK("provision nodes", err)
+		defer destroyFn()
+
+		g.OK("base installer", g.SetInstaller(nodes, param.BaseInstallerURL, "base"))
+		g.OK("install", g.OfflineInstall(nodes, param.InstallParam))
+		g.OK("status", g.Status(nodes))
+		workload := deployWorkload(g, nodes, nodes[0], param.installParam)
+
+		g.OK(fmt.Sprintf("upgrade failed at %v and rolled back", param.FailPhase),
+			g.UpgradeRollback(nodes, cfg.InstallerURL, "upgrade", param.FailPhase))
+		g.OK("status", g.Status(nodes))
+		verifyWorkload(g, nodes, workload, "rollback")
+	}, nil
+}
@@ -160,6 +160,15 @@ func TestMain(t *testing.T) {
 	fmt.Println("\n******** TEST SUITE COMPLETED **********")
 	for _, res := range result {
 		fmt.Printf("%s %s %s %s\n", res.Status, res.Name, xlog.ToJSON(res.Param), res.LogUrl)
+		if res.Health != nil && !res.Health.Healthy() {
+			fmt.Printf("\tcluster health: %s\n", strings.Join(res.Health.Problems(), "; "))
+		}
+		for _, timing := range res.Timings {
+			fmt.Printf("\t%s: %v\n", timing.Name, timing.Elapsed)
+		}
+		for _, report := range res.Availability {
+			fmt.Printf("\tavailability: %s\n", report)
+		}
 	}
 
 }